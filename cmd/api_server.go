@@ -3,23 +3,30 @@ package cmd
 import (
 	"fmt"
 	"log"
+	"net"
 	"net/http"
-	"time"
 
 	"github.com/Depado/ginprom"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-contrib/pprof"
 	"github.com/gin-gonic/gin"
 	"github.com/map-services/placenames-api/internal"
+	"github.com/map-services/placenames-api/internal/grpcapi"
 	"github.com/map-services/placenames-api/internal/routes"
+	placenamesv1 "github.com/map-services/placenames-api/pkg/pb/placenamesv1"
 	"github.com/rm-hull/godx"
 	healthcheck "github.com/tavsec/gin-healthcheck"
 	"github.com/tavsec/gin-healthcheck/checks"
 	hc_config "github.com/tavsec/gin-healthcheck/config"
-	cachecontrol "go.eigsys.de/gin-cachecontrol/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
 )
 
-func ApiServer(filePath string, port int, debug bool, topK int) error {
+// spatialCellSize is the size (in degrees) of the spatial index grid cells:
+// roughly 11km north-south, giving a handful of places per cell in the UK.
+const spatialCellSize = 0.1
+
+func ApiServer(filePath string, port int, grpcPort int, debug bool, topK int, publicURL string) error {
 
 	godx.GitVersion()
 	godx.EnvironmentVars()
@@ -29,6 +36,8 @@ func ApiServer(filePath string, port int, debug bool, topK int) error {
 	if err != nil {
 		return fmt.Errorf("error loading data: %w", err)
 	}
+	spatial := internal.NewSpatialIndex(trie.Places(), spatialCellSize)
+	log.Printf("Indexed %d located places", spatial.Len())
 
 	r := gin.New()
 
@@ -39,7 +48,8 @@ func ApiServer(filePath string, port int, debug bool, topK int) error {
 	)
 
 	r.Use(
-		gin.Recovery(),
+		routes.RequestID(),
+		gin.CustomRecovery(routes.Recovery),
 		gin.LoggerWithWriter(gin.DefaultWriter, "/healthz", "/metrics"),
 		prometheus.Instrument(),
 		cors.Default(),
@@ -54,13 +64,15 @@ func ApiServer(filePath string, port int, debug bool, topK int) error {
 		return fmt.Errorf("failed to initialize healthcheck: %w", err)
 	}
 
-	v1 := r.Group("/v1")
-	v1.Use(cachecontrol.New(cachecontrol.Config{
-		MaxAge:    cachecontrol.Duration(28 * 24 * time.Hour),
-		Immutable: true,
-		Public:    true,
-	}))
-	v1.GET("/place-names/prefix/:query", routes.Prefix(trie))
+	if err = routes.Register(r, trie, spatial, publicURL); err != nil {
+		return fmt.Errorf("failed to register routes: %w", err)
+	}
+
+	if grpcPort != 0 {
+		if err = startGRPCServer(trie, grpcPort); err != nil {
+			return err
+		}
+	}
 
 	addr := fmt.Sprintf(":%d", port)
 	log.Printf("Starting HTTP API Server on port %d...", port)
@@ -69,3 +81,24 @@ func ApiServer(filePath string, port int, debug bool, topK int) error {
 	}
 	return nil
 }
+
+// startGRPCServer serves the gRPC API in the background, alongside the HTTP
+// API and over the same trie.
+func startGRPCServer(trie *internal.Trie, port int) error {
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return fmt.Errorf("failed to listen for gRPC on port %d: %w", port, err)
+	}
+
+	server := grpc.NewServer()
+	placenamesv1.RegisterPlaceNamesServiceServer(server, grpcapi.NewServer(trie))
+	reflection.Register(server)
+
+	log.Printf("Starting gRPC API Server on port %d...", port)
+	go func() {
+		if err := server.Serve(lis); err != nil {
+			log.Printf("gRPC API Server on port %d stopped: %v", port, err)
+		}
+	}()
+	return nil
+}
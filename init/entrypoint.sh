@@ -19,3 +19,12 @@ rm -f $DATABASE
 sqlite3 $DATABASE < /app/init.sql
 
 echo "Done! Database is at $DATABASE"
+
+RELEVANCY="/data/placenames_with_relevancy.csv.gz"
+if [ -f "$RELEVANCY" ]; then
+  echo "Exporting place attributes..."
+  gunzip -c "$RELEVANCY" > /app/relevancy.csv
+  sqlite3 $DATABASE < /app/export.sql
+  gzip -c /app/placenames_with_attributes.csv > /data/placenames_with_attributes.csv.gz
+  echo "Done! Data file is at /data/placenames_with_attributes.csv.gz"
+fi
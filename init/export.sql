@@ -0,0 +1,18 @@
+-- Appends the attributes the API server reads (see the README's "Optional
+-- columns") to the place names with relevancy scores, joining on the name.
+-- Every place sharing a name gets its relevancy.
+.mode csv
+.import /app/relevancy.csv relevancy
+
+.headers on
+.output /app/placenames_with_attributes.csv
+SELECT p.place23nm AS Placename, r.Relevancy,
+       p.place23cd, p.descnm, p.lat, p.long, p.gridgb1e, p.gridgb1n, p.grid1km,
+       p.ctry23nm, p.rgn23nm, p.cty23nm, p.lad23nm, p.npark23nm
+FROM place_names p
+JOIN relevancy r ON r.Placename = p.place23nm
+GROUP BY p.place23cd
+ORDER BY p.place23nm, p.place23cd;
+.output stdout
+
+DROP TABLE relevancy;
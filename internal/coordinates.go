@@ -0,0 +1,114 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// coordinatePart matches a single latitude or longitude, either as signed
+// decimal degrees ("-0.1278") or degrees/minutes/seconds with an optional
+// hemisphere ("51°30'26\"N", "0°7'W", "51.5074N").
+const coordinatePart = `([-+]?\d+(?:\.\d+)?)\s*°?\s*` +
+	`(?:(\d+(?:\.\d+)?)\s*'\s*)?` +
+	`(?:(\d+(?:\.\d+)?)\s*"\s*)?` +
+	`([NSEWnsew])?`
+
+// coordinateMarkers are the characters telling coordinates apart from two
+// bare numbers, such as "1 2", which are more likely part of a name or
+// address: a decimal point, degree sign, hemisphere or separator.
+const coordinateMarkers = `.°NSEWnsew,;`
+
+var (
+	coordinatesRegex = regexp.MustCompile(`^\s*` + coordinatePart + `\s*(?:[,;]\s*|\s+)` + coordinatePart + `\s*$`)
+	what3wordsRegex  = regexp.MustCompile(`^\s*(?:///)?\p{L}+\.\p{L}+\.\p{L}+\s*$`)
+
+	// normalise the various typographic primes & quotes people paste in
+	primeReplacer = strings.NewReplacer("′", "'", "’", "'", "‘", "'", "″", `"`, "”", `"`, "“", `"`, "''", `"`, "º", "°", "˚", "°")
+)
+
+var ErrNotCoordinates = errors.New("not a coordinate string")
+
+// LooksLikeWhat3Words reports whether the query is a what3words-style
+// address (e.g. "///filled.count.soap"), which cannot be resolved locally.
+func LooksLikeWhat3Words(query string) bool {
+	return what3wordsRegex.MatchString(query)
+}
+
+// ParseCoordinates parses a latitude/longitude pair in decimal degrees or
+// degrees/minutes/seconds notation, returning WGS84 decimal degrees. If the
+// query doesn't look like coordinates at all, ErrNotCoordinates is returned.
+func ParseCoordinates(query string) (float64, float64, error) {
+	query = primeReplacer.Replace(query)
+	m := coordinatesRegex.FindStringSubmatch(query)
+	if m == nil || !strings.ContainsAny(query, coordinateMarkers) {
+		return 0, 0, ErrNotCoordinates
+	}
+
+	first, firstHemi, err := parseCoordinatePart(m[1], m[2], m[3], m[4])
+	if err != nil {
+		return 0, 0, err
+	}
+	second, secondHemi, err := parseCoordinatePart(m[5], m[6], m[7], m[8])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	lat, long := first, second
+	switch {
+	case isLongitudeHemisphere(firstHemi) && !isLongitudeHemisphere(secondHemi):
+		lat, long = second, first
+	case firstHemi != "" && secondHemi != "" && isLongitudeHemisphere(firstHemi) == isLongitudeHemisphere(secondHemi):
+		return 0, 0, fmt.Errorf("conflicting hemispheres in %q", query)
+	}
+
+	if lat < -90 || lat > 90 {
+		return 0, 0, fmt.Errorf("latitude out of range: %f", lat)
+	}
+	if long < -180 || long > 180 {
+		return 0, 0, fmt.Errorf("longitude out of range: %f", long)
+	}
+	return lat, long, nil
+}
+
+func isLongitudeHemisphere(hemi string) bool {
+	return hemi == "E" || hemi == "W"
+}
+
+func parseCoordinatePart(degrees, minutes, seconds, hemisphere string) (float64, string, error) {
+	value, err := strconv.ParseFloat(degrees, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid degrees %q: %w", degrees, err)
+	}
+
+	negative := strings.HasPrefix(degrees, "-")
+	if negative {
+		value = -value
+	}
+
+	for i, part := range []string{minutes, seconds} {
+		if part == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil || v >= 60 {
+			return 0, "", fmt.Errorf("invalid minutes/seconds %q", part)
+		}
+		if i == 0 {
+			value += v / 60
+		} else {
+			value += v / 3600
+		}
+	}
+
+	hemisphere = strings.ToUpper(hemisphere)
+	if negative && hemisphere != "" {
+		return 0, "", fmt.Errorf("cannot combine a negative sign with hemisphere %s", hemisphere)
+	}
+	if negative || hemisphere == "S" || hemisphere == "W" {
+		value = -value
+	}
+	return value, hemisphere, nil
+}
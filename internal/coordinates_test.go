@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseCoordinates(t *testing.T) {
+	t.Run("valid coordinates", func(t *testing.T) {
+		tests := []struct {
+			query string
+			lat   float64
+			long  float64
+		}{
+			{"51.5074, -0.1278", 51.5074, -0.1278},
+			{"51.5074 -0.1278", 51.5074, -0.1278},
+			{"51.5074;-0.1278", 51.5074, -0.1278},
+			{"51°30'N 0°7'W", 51.5, -0.116667},
+			{"51°30′26″N, 0°7′39″W", 51.507222, -0.1275},
+			{"0°7'39\"W 51°30'26\"N", 51.507222, -0.1275},
+			{"51.5074N 0.1278W", 51.5074, -0.1278},
+			{"-33.8688, 151.2093", -33.8688, 151.2093},
+			{"51.5074N, -0.1278", 51.5074, -0.1278},
+			{"51, 0", 51, 0},
+			{"51°N 1°W", 51, -1},
+		}
+
+		for _, tt := range tests {
+			lat, long, err := ParseCoordinates(tt.query)
+			if err != nil {
+				t.Fatalf("%q: expected no error, got %v", tt.query, err)
+			}
+			if math.Abs(lat-tt.lat) > 1e-5 || math.Abs(long-tt.long) > 1e-5 {
+				t.Errorf("%q: expected (%f, %f), got (%f, %f)", tt.query, tt.lat, tt.long, lat, long)
+			}
+		}
+	})
+
+	t.Run("not coordinates", func(t *testing.T) {
+		for _, query := range []string{"London", "51", "1 2", "-51 0", "SU 123 456", "St. Albans", ""} {
+			if _, _, err := ParseCoordinates(query); !errors.Is(err, ErrNotCoordinates) {
+				t.Errorf("%q: expected ErrNotCoordinates, got %v", query, err)
+			}
+		}
+	})
+
+	t.Run("invalid coordinates", func(t *testing.T) {
+		for _, query := range []string{"91.0, 0.0", "51.5, 181", "51°75'N 0°7'W", "51N 52N", "-51N 0W"} {
+			_, _, err := ParseCoordinates(query)
+			if err == nil || errors.Is(err, ErrNotCoordinates) {
+				t.Errorf("%q: expected a validation error, got %v", query, err)
+			}
+		}
+	})
+
+	t.Run("what3words", func(t *testing.T) {
+		for _, query := range []string{"///filled.count.soap", "index.home.raft"} {
+			if !LooksLikeWhat3Words(query) {
+				t.Errorf("%q: expected to look like what3words", query)
+			}
+		}
+		for _, query := range []string{"St. Albans", "51.5, -0.12", "London"} {
+			if LooksLikeWhat3Words(query) {
+				t.Errorf("%q: did not expect to look like what3words", query)
+			}
+		}
+	})
+}
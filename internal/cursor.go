@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// Cursors are opaque to clients: they hold the offset of the next page and
+// a hash of the search, so a cursor can't be replayed against another search.
+// The search is given as a key covering everything which decides its pages:
+// the query, and any filters, ranking and page size.
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+func EncodeCursor(search string, offset int) string {
+	raw := fmt.Sprintf("%d:%08x", offset, queryHash(search))
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor returns the offset held in the cursor, provided it was
+// issued for the same search.
+func DecodeCursor(cursor, search string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	offsetStr, hash, ok := strings.Cut(string(raw), ":")
+	if !ok || hash != fmt.Sprintf("%08x", queryHash(search)) {
+		return 0, ErrInvalidCursor
+	}
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 {
+		return 0, ErrInvalidCursor
+	}
+	return offset, nil
+}
+
+func queryHash(search string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(search))
+	return h.Sum32()
+}
@@ -0,0 +1,34 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCursor(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		cursor := EncodeCursor("Ba", 150)
+		offset, err := DecodeCursor(cursor, "Ba")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if offset != 150 {
+			t.Errorf("expected offset 150, got %d", offset)
+		}
+	})
+
+	t.Run("different query", func(t *testing.T) {
+		cursor := EncodeCursor("Ba", 150)
+		if _, err := DecodeCursor(cursor, "Bb"); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("expected ErrInvalidCursor, got %v", err)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, cursor := range []string{"", "!!!", "MTUw", "LTE6MDAwMDAwMDA"} {
+			if _, err := DecodeCursor(cursor, "Ba"); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("%q: expected ErrInvalidCursor, got %v", cursor, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"slices"
+	"strings"
+)
+
+// FuzzyOptions control how far FindFuzzy strays from the query
+type FuzzyOptions struct {
+	MaxEdits       int  // more than 2 matches too much to be useful
+	PrefixLength   int  // leading runes which must match exactly
+	Transpositions bool // count swapping adjacent runes as one edit, not two
+	WholeName      bool // match the whole name, not just its start
+}
+
+// FuzzyMatch is a place found by FindFuzzy, with the number of edits between
+// the query and the start of its name (or all of it, with WholeName).
+type FuzzyMatch struct {
+	Place *Place
+	Edits int
+}
+
+// FindFuzzy returns up to limit places which start with something within
+// MaxEdits insertions, deletions or substitutions (and transpositions, if
+// enabled) of the prefix, or whose whole name is, with WholeName. Matches
+// needing the fewest edits come first, then the most relevant.
+//
+// The trie is walked with a row of the edit distance matrix per node,
+// abandoning a subtree as soon as every entry in its row is too large.
+func (t *Trie) FindFuzzy(prefix string, opts FuzzyOptions, limit int, accept func(*Place) bool) []FuzzyMatch {
+	query := []rune(strings.ToLower(prefix))
+	if len(query) == 0 || limit <= 0 {
+		return []FuzzyMatch{}
+	}
+
+	search := fuzzySearch{query: query, opts: opts, matched: make([][]*TrieNode, opts.MaxEdits+1)}
+	firstRow := make([]int, len(query)+1)
+	for j := range firstRow {
+		firstRow[j] = j
+	}
+	search.visit(t.root, 0, 0, nil, firstRow, opts.MaxEdits+1)
+
+	// a node's whole subtree matches a prefix, so take the best places from
+	// each node, skipping those already found with fewer edits
+	seen := make(map[*Place]bool)
+	results := make([]FuzzyMatch, 0, limit)
+	for edits, nodes := range search.matched {
+		var level []*Place
+		for _, node := range nodes {
+			places := t.walkNode(node)
+			if opts.WholeName {
+				places = slices.Values(node.Terminals)
+			}
+			found := 0
+			for place := range places {
+				if seen[place] || (accept != nil && !accept(place)) {
+					continue
+				}
+				seen[place] = true
+				level = append(level, place)
+				if found++; found == limit {
+					break
+				}
+			}
+		}
+
+		SortByRelevancy(level)
+		for _, place := range level[:min(limit-len(results), len(level))] {
+			results = append(results, FuzzyMatch{Place: place, Edits: edits})
+		}
+		if len(results) == limit {
+			break
+		}
+	}
+	return results
+}
+
+type fuzzySearch struct {
+	query   []rune
+	opts    FuzzyOptions
+	matched [][]*TrieNode // by the number of edits
+}
+
+// visit computes the row for each child of the node (at the given depth,
+// reached by char), recording the children which match with fewer edits
+// than any ancestor did. For whole names, it's the children where names end
+// that are recorded, however their ancestors matched.
+func (s *fuzzySearch) visit(node *TrieNode, depth int, char rune, prevRow, row []int, ancestorEdits int) {
+	n := len(s.query)
+	for r, child := range node.Children {
+		if depth < s.opts.PrefixLength && (depth >= n || r != s.query[depth]) {
+			continue
+		}
+
+		next := make([]int, n+1)
+		next[0] = depth + 1
+		for j := 1; j <= n; j++ {
+			cost := 1
+			if s.query[j-1] == r {
+				cost = 0
+			}
+			next[j] = min(row[j]+1, next[j-1]+1, row[j-1]+cost)
+			if s.opts.Transpositions && depth > 0 && j > 1 && r == s.query[j-2] && char == s.query[j-1] {
+				next[j] = min(next[j], prevRow[j-2]+1)
+			}
+		}
+
+		if s.opts.WholeName {
+			if len(child.Terminals) > 0 && next[n] <= s.opts.MaxEdits {
+				s.matched[next[n]] = append(s.matched[next[n]], child)
+			}
+			if slices.Min(next) <= s.opts.MaxEdits {
+				s.visit(child, depth+1, r, row, next, ancestorEdits)
+			}
+			continue
+		}
+
+		edits := ancestorEdits
+		if next[n] < ancestorEdits {
+			edits = next[n]
+			s.matched[edits] = append(s.matched[edits], child)
+		}
+		if edits > 0 && slices.Min(next) <= s.opts.MaxEdits {
+			s.visit(child, depth+1, r, row, next, edits)
+		}
+	}
+}
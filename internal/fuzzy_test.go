@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestFindFuzzy(t *testing.T) {
+	trie := NewTrie(10)
+	for _, place := range []*Place{
+		{Name: "London", Relevancy: 0.9, Country: "England"},
+		{Name: "Londonderry", Relevancy: 0.7, Country: "Northern Ireland"},
+		{Name: "Lydney", Relevancy: 0.4, Country: "England"},
+		{Name: "Loughton", Relevancy: 0.5, Country: "England"},
+		{Name: "Bristol", Relevancy: 0.8, Country: "England"},
+	} {
+		trie.Insert(place)
+	}
+	names := func(matches []FuzzyMatch) []string {
+		result := make([]string, len(matches))
+		for i, match := range matches {
+			result[i] = match.Place.Name
+		}
+		return result
+	}
+
+	t.Run("exact prefixes come first", func(t *testing.T) {
+		matches := trie.FindFuzzy("lond", FuzzyOptions{MaxEdits: 1}, 10, nil)
+		if len(matches) < 2 || matches[0].Place.Name != "London" || matches[1].Place.Name != "Londonderry" {
+			t.Fatalf("expected London and Londonderry first, got %v", names(matches))
+		}
+		for _, match := range matches[:2] {
+			if match.Edits != 0 {
+				t.Errorf("expected %s to need no edits, got %d", match.Place.Name, match.Edits)
+			}
+		}
+	})
+
+	t.Run("typos", func(t *testing.T) {
+		for _, query := range []string{"lindon", "lndon", "loondon", "Brsitol"} {
+			matches := trie.FindFuzzy(query, FuzzyOptions{MaxEdits: 1, Transpositions: true}, 1, nil)
+			if len(matches) != 1 || matches[0].Edits != 1 {
+				t.Errorf("%s: expected one match with one edit, got %v", query, names(matches))
+			}
+		}
+	})
+
+	t.Run("transpositions count as two edits unless enabled", func(t *testing.T) {
+		if matches := trie.FindFuzzy("brsitol", FuzzyOptions{MaxEdits: 1}, 10, nil); len(matches) != 0 {
+			t.Errorf("expected no matches, got %v", names(matches))
+		}
+		if matches := trie.FindFuzzy("brsitol", FuzzyOptions{MaxEdits: 2}, 10, nil); len(matches) != 1 || matches[0].Edits != 2 {
+			t.Errorf("expected Bristol with two edits, got %v", names(matches))
+		}
+	})
+
+	t.Run("prefix length must match exactly", func(t *testing.T) {
+		if matches := trie.FindFuzzy("kondon", FuzzyOptions{MaxEdits: 1, PrefixLength: 1}, 10, nil); len(matches) != 0 {
+			t.Errorf("expected no matches, got %v", names(matches))
+		}
+		if matches := trie.FindFuzzy("kondon", FuzzyOptions{MaxEdits: 1}, 10, nil); len(matches) != 2 {
+			t.Errorf("expected London and Londonderry, got %v", names(matches))
+		}
+	})
+
+	t.Run("whole names", func(t *testing.T) {
+		matches := trie.FindFuzzy("londn", FuzzyOptions{MaxEdits: 2, WholeName: true}, 10, nil)
+		if len(matches) != 1 || matches[0].Place.Name != "London" || matches[0].Edits != 1 {
+			t.Errorf("expected only London with one edit, got %v", names(matches))
+		}
+		matches = trie.FindFuzzy("londonderri", FuzzyOptions{MaxEdits: 1, WholeName: true}, 10, nil)
+		if len(matches) != 1 || matches[0].Place.Name != "Londonderry" {
+			t.Errorf("expected only Londonderry, got %v", names(matches))
+		}
+	})
+
+	t.Run("filter and limit", func(t *testing.T) {
+		filter := PlaceFilter{Country: "england"}
+		matches := trie.FindFuzzy("lodnon", FuzzyOptions{MaxEdits: 2, Transpositions: true}, 10, filter.Predicate())
+		if len(matches) == 0 || matches[0].Place.Name != "London" {
+			t.Fatalf("expected London first, got %v", names(matches))
+		}
+		for _, match := range matches {
+			if match.Place.Name == "Londonderry" {
+				t.Error("expected Londonderry to be filtered out")
+			}
+		}
+		if matches := trie.FindFuzzy("l", FuzzyOptions{MaxEdits: 1}, 2, nil); len(matches) != 2 {
+			t.Errorf("expected 2 matches, got %v", names(matches))
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package internal
+
+import "math"
+
+const earthRadiusKm = 6371.0088
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+// HaversineKm returns the great-circle distance in kilometres between two
+// WGS84 points given in degrees.
+func HaversineKm(lat1, long1, lat2, long2 float64) float64 {
+	dLat := toRadians(lat2 - lat1)
+	dLong := toRadians(long2 - long1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLong/2)*math.Sin(dLong/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+const kmPerMile = 1.609344
+
+func KmToMiles(km float64) float64 {
+	return km / kmPerMile
+}
+
+// InitialBearing returns the initial great-circle bearing in degrees
+// (0-360, clockwise from north) from the first point towards the second.
+func InitialBearing(lat1, long1, lat2, long2 float64) float64 {
+	phi1, phi2 := toRadians(lat1), toRadians(lat2)
+	dLong := toRadians(long2 - long1)
+	y := math.Sin(dLong) * math.Cos(phi2)
+	x := math.Cos(phi1)*math.Sin(phi2) - math.Sin(phi1)*math.Cos(phi2)*math.Cos(dLong)
+	bearing := math.Atan2(y, x) * 180 / math.Pi
+	return math.Mod(bearing+360, 360)
+}
+
+var compassPoints = []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+
+// CompassPoint converts a bearing in degrees to one of the 16 compass points.
+func CompassPoint(bearing float64) string {
+	idx := int(math.Round(math.Mod(bearing+360, 360)/22.5)) % len(compassPoints)
+	return compassPoints[idx]
+}
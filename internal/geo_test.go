@@ -0,0 +1,45 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGeo(t *testing.T) {
+	t.Run("haversine distance", func(t *testing.T) {
+		dist := HaversineKm(51.5072, -0.1276, 55.9533, -3.1883)
+		if math.Abs(dist-534) > 2 {
+			t.Errorf("expected London to Edinburgh to be approx 534km, got %.1f", dist)
+		}
+		if miles := KmToMiles(dist); math.Abs(miles-332) > 2 {
+			t.Errorf("expected approx 332 miles, got %.1f", miles)
+		}
+	})
+
+	t.Run("initial bearing", func(t *testing.T) {
+		tests := []struct {
+			lat2, long2 float64
+			expected    float64
+		}{
+			{52, 0, 0},
+			{51, 0, 180},
+			{51.5, 1, 90},
+			{51.5, -1, 270},
+		}
+		for _, tt := range tests {
+			bearing := InitialBearing(51.5, 0, tt.lat2, tt.long2)
+			if math.Abs(bearing-tt.expected) > 0.5 {
+				t.Errorf("to (%f, %f): expected bearing approx %f, got %f", tt.lat2, tt.long2, tt.expected, bearing)
+			}
+		}
+	})
+
+	t.Run("compass points", func(t *testing.T) {
+		tests := map[float64]string{0: "N", 11: "N", 12: "NNE", 45: "NE", 180: "S", 350: "N", 337.5: "NNW", -90: "W"}
+		for bearing, expected := range tests {
+			if got := CompassPoint(bearing); got != expected {
+				t.Errorf("%f: expected %s, got %s", bearing, expected, got)
+			}
+		}
+	})
+}
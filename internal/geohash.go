@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	geohashAlphabet     = "0123456789bcdefghjkmnpqrstuvwxyz"
+	GeohashPrecision    = 9  // ~5m cells, stored for every located place
+	MaxGeohashPrecision = 12 // the longest geohash, of a 60-bit cell
+)
+
+// EncodeGeohash returns the geohash of the point at the given precision.
+func EncodeGeohash(lat, long float64, precision int) string {
+	latRange := [2]float64{-90, 90}
+	longRange := [2]float64{-180, 180}
+
+	var sb strings.Builder
+	bit, ch, even := 0, 0, true
+	for sb.Len() < precision {
+		rng, value := &latRange, lat
+		if even {
+			rng, value = &longRange, long
+		}
+
+		mid := (rng[0] + rng[1]) / 2
+		ch <<= 1
+		if value >= mid {
+			ch |= 1
+			rng[0] = mid
+		} else {
+			rng[1] = mid
+		}
+		even = !even
+
+		if bit++; bit == 5 {
+			sb.WriteByte(geohashAlphabet[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return sb.String()
+}
+
+// ParseGeohash normalizes and validates a geohash (prefix) supplied by a
+// client. Places only have geohashes of GeohashPrecision, so no longer
+// prefix could match any of them.
+func ParseGeohash(geohash string) (string, error) {
+	geohash = strings.ToLower(strings.TrimSpace(geohash))
+	if geohash == "" || len(geohash) > GeohashPrecision {
+		return "", fmt.Errorf("geohash must be between 1 and %d characters", GeohashPrecision)
+	}
+	if strings.Trim(geohash, geohashAlphabet) != "" {
+		return "", fmt.Errorf("geohash may only contain the characters %s", geohashAlphabet)
+	}
+	return geohash, nil
+}
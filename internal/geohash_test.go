@@ -0,0 +1,33 @@
+package internal
+
+import "testing"
+
+func TestGeohash(t *testing.T) {
+	t.Run("encode", func(t *testing.T) {
+		tests := []struct {
+			lat, long float64
+			precision int
+			expected  string
+		}{
+			{51.5072, -0.1276, 6, "gcpvj0"},
+			{57.64911, 10.40744, 11, "u4pruydqqvj"},
+			{-33.8688, 151.2093, 5, "r3gx2"},
+		}
+		for _, tt := range tests {
+			if got := EncodeGeohash(tt.lat, tt.long, tt.precision); got != tt.expected {
+				t.Errorf("(%f, %f): expected %s, got %s", tt.lat, tt.long, tt.expected, got)
+			}
+		}
+	})
+
+	t.Run("parse", func(t *testing.T) {
+		if got, err := ParseGeohash(" GCPV "); err != nil || got != "gcpv" {
+			t.Errorf("expected gcpv, got %q (%v)", got, err)
+		}
+		for _, invalid := range []string{"", "gcpva", "gcpvj0e54x", "gcpvj0gcpvj0g", "london"} {
+			if _, err := ParseGeohash(invalid); err == nil {
+				t.Errorf("%q: expected an error, got nil", invalid)
+			}
+		}
+	})
+}
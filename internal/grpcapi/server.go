@@ -0,0 +1,151 @@
+// Package grpcapi implements the PlaceNamesService gRPC API over the same
+// trie as the HTTP API.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/map-services/placenames-api/internal"
+	pb "github.com/map-services/placenames-api/pkg/pb/placenamesv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const defaultMaxResults = 10
+
+type Server struct {
+	pb.UnimplementedPlaceNamesServiceServer
+	trie *internal.Trie
+}
+
+func NewServer(trie *internal.Trie) *Server {
+	return &Server{trie: trie}
+}
+
+func (s *Server) Suggest(ctx context.Context, req *pb.SuggestRequest) (*pb.SuggestResponse, error) {
+	results, err := s.suggest(ctx, req.GetQuery(), req.GetMaxResults(), req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SuggestResponse{Results: results}, nil
+}
+
+// Autocomplete reads keystrokes on one goroutine while searching on
+// another. Only the latest unsearched keystroke is kept, and a search is
+// abandoned (without a response) as soon as a newer keystroke arrives.
+func (s *Server) Autocomplete(stream grpc.BidiStreamingServer[pb.AutocompleteRequest, pb.AutocompleteResponse]) error {
+	session := internal.NewTypeahead[*pb.AutocompleteRequest](stream.Context())
+	recvErr := make(chan error, 1)
+
+	go func() {
+		defer session.Close()
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			session.Push(req)
+		}
+	}()
+
+	for keystroke := range session.Keystrokes() {
+		req := keystroke.Request
+		results, err := s.suggest(keystroke.Ctx, req.GetQuery(), req.GetMaxResults(), req.GetFilter())
+		if keystroke.Ctx.Err() != nil && stream.Context().Err() == nil {
+			continue // superseded
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&pb.AutocompleteResponse{
+			Sequence: req.GetSequence(),
+			Query:    req.GetQuery(),
+			Results:  results,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := <-recvErr; !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// suggest finds the top places for the prefix
+func (s *Server) suggest(ctx context.Context, query string, maxResults int32, filter *pb.Filter) ([]*pb.Place, error) {
+	limit, err := s.parseMaxResults(maxResults)
+	if err != nil {
+		return nil, err
+	}
+	placeFilter, err := toPlaceFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	places, err := s.trie.FindByPrefixContext(ctx, query, limit, placeFilter.Predicate())
+	if err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	results := make([]*pb.Place, len(places))
+	for i, place := range places {
+		results[i] = toPlace(place)
+	}
+	return results, nil
+}
+
+func (s *Server) parseMaxResults(maxResults int32) (int, error) {
+	switch {
+	case maxResults == 0:
+		return min(defaultMaxResults, s.trie.TopK()), nil
+	case maxResults < 0 || int(maxResults) > s.trie.TopK():
+		return 0, status.Errorf(codes.InvalidArgument, "max_results must be a positive integer less than or equal to %d", s.trie.TopK())
+	default:
+		return int(maxResults), nil
+	}
+}
+
+func toPlaceFilter(filter *pb.Filter) (internal.PlaceFilter, error) {
+	placeFilter := internal.PlaceFilter{
+		Country:        strings.TrimSpace(filter.GetCountry()),
+		Region:         strings.TrimSpace(filter.GetRegion()),
+		County:         strings.TrimSpace(filter.GetCounty()),
+		LocalAuthority: strings.TrimSpace(filter.GetLocalAuthority()),
+		NationalPark:   strings.TrimSpace(filter.GetNationalPark()),
+	}
+	for _, placeType := range filter.GetTypes() {
+		if normalized := internal.NormalizePlaceType(placeType); normalized != "" {
+			placeFilter.Types = append(placeFilter.Types, normalized)
+		}
+	}
+	if geohash := filter.GetGeohash(); geohash != "" {
+		var err error
+		if placeFilter.Geohash, err = internal.ParseGeohash(geohash); err != nil {
+			return placeFilter, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	return placeFilter, nil
+}
+
+func toPlace(place *internal.Place) *pb.Place {
+	result := &pb.Place{
+		Id:             place.ID,
+		Name:           place.Name,
+		Relevancy:      place.Relevancy,
+		Type:           place.Type,
+		Country:        place.Country,
+		Region:         place.Region,
+		County:         place.County,
+		LocalAuthority: place.LocalAuthority,
+		NationalPark:   place.NationalPark,
+	}
+	if place.HasLocation() {
+		result.Lat, result.Long = &place.Lat, &place.Long
+	}
+	return result
+}
@@ -0,0 +1,147 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/map-services/placenames-api/internal"
+	pb "github.com/map-services/placenames-api/pkg/pb/placenamesv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestClient(t *testing.T) pb.PlaceNamesServiceClient {
+	t.Helper()
+
+	trie := internal.NewTrie(10)
+	for _, place := range []*internal.Place{
+		{ID: "london", Name: "London", Relevancy: 1.0, Lat: 51.5072, Long: -0.1276, Country: "England"},
+		{ID: "londonderry", Name: "Londonderry", Relevancy: 0.8, Country: "Northern Ireland"},
+		{ID: "long-eaton", Name: "Long Eaton", Relevancy: 0.6, Country: "England"},
+		{ID: "luton", Name: "Luton", Relevancy: 0.7, Country: "England"},
+	} {
+		trie.Insert(place)
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	pb.RegisterPlaceNamesServiceServer(server, NewServer(trie))
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return pb.NewPlaceNamesServiceClient(conn)
+}
+
+func ids(places []*pb.Place) []string {
+	result := make([]string, len(places))
+	for i, place := range places {
+		result[i] = place.GetId()
+	}
+	return result
+}
+
+func TestSuggest(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	t.Run("returns matches in relevancy order", func(t *testing.T) {
+		resp, err := client.Suggest(ctx, &pb.SuggestRequest{Query: "lon"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		got := ids(resp.GetResults())
+		if len(got) != 3 || got[0] != "london" || got[1] != "londonderry" || got[2] != "long-eaton" {
+			t.Errorf("expected london, londonderry, long-eaton, got %v", got)
+		}
+		if resp.GetResults()[0].Lat == nil || resp.GetResults()[1].Lat != nil {
+			t.Errorf("expected only London to have a location, got %v", resp.GetResults())
+		}
+	})
+
+	t.Run("applies max_results and filter", func(t *testing.T) {
+		resp, err := client.Suggest(ctx, &pb.SuggestRequest{
+			Query:      "l",
+			MaxResults: 2,
+			Filter:     &pb.Filter{Country: "england"},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if got := ids(resp.GetResults()); len(got) != 2 || got[0] != "london" || got[1] != "luton" {
+			t.Errorf("expected london and luton, got %v", got)
+		}
+	})
+
+	t.Run("rejects invalid arguments", func(t *testing.T) {
+		for name, req := range map[string]*pb.SuggestRequest{
+			"max_results too large": {Query: "l", MaxResults: 11},
+			"negative max_results":  {Query: "l", MaxResults: -1},
+			"invalid geohash":       {Query: "l", Filter: &pb.Filter{Geohash: "ail"}},
+		} {
+			if _, err := client.Suggest(ctx, req); status.Code(err) != codes.InvalidArgument {
+				t.Errorf("%s: expected InvalidArgument, got %v", name, err)
+			}
+		}
+	})
+}
+
+func TestAutocomplete(t *testing.T) {
+	client := newTestClient(t)
+
+	stream, err := client.Autocomplete(context.Background())
+	if err != nil {
+		t.Fatalf("failed to open stream: %v", err)
+	}
+	for i, query := range []string{"l", "lo", "lon", "lond"} {
+		if err := stream.Send(&pb.AutocompleteRequest{Query: query, Sequence: uint64(i + 1)}); err != nil {
+			t.Fatalf("failed to send: %v", err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+
+	// earlier keystrokes may or may not have been dropped, but responses
+	// arrive in order and the last keystroke is always answered
+	var last *pb.AutocompleteResponse
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if last != nil && resp.GetSequence() <= last.GetSequence() {
+			t.Errorf("expected increasing sequences, got %d after %d", resp.GetSequence(), last.GetSequence())
+		}
+		last = resp
+	}
+
+	if last == nil || last.GetSequence() != 4 || last.GetQuery() != "lond" {
+		t.Fatalf("expected the last response to be for sequence 4, got %v", last)
+	}
+	if got := ids(last.GetResults()); len(got) != 2 || got[0] != "london" || got[1] != "londonderry" {
+		t.Errorf("expected london and londonderry, got %v", got)
+	}
+}
@@ -2,15 +2,96 @@ package internal
 
 import (
 	"compress/gzip"
+	"crypto/sha256"
 	"encoding/csv"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"os"
 	"strconv"
+	"strings"
 )
 
-func LoadCSV(filename string, action func(location string, score float64) error) (int, error) {
+// Optional columns, matched by (case-insensitive) header name. These use the
+// ONS Index of Place Names column names, so extra fields exported from the
+// init-db SQLite database can be appended after the name & relevancy.
+const (
+	colLat      = "lat"
+	colLong     = "long"
+	colEasting  = "gridgb1e"
+	colNorthing = "gridgb1n"
+	colGrid1km  = "grid1km"
+	colCountry  = "ctry23nm"
+	colRegion   = "rgn23nm"
+	colCounty   = "cty23nm"
+	colLAD      = "lad23nm"
+	colNPark    = "npark23nm"
+	colType     = "descnm"
+	colID       = "place23cd"
+)
+
+type csvColumns map[string]int
+
+func (cols csvColumns) get(rec []string, name string) string {
+	if idx, ok := cols[name]; ok && idx < len(rec) {
+		return strings.TrimSpace(rec[idx])
+	}
+	return ""
+}
+
+func (cols csvColumns) has(names ...string) bool {
+	for _, name := range names {
+		if _, ok := cols[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// missingFeatures describes what won't work for want of optional columns.
+// The bundled data file has only names and relevancies, so without the
+// attributes exported from the init-db database, the location, area and
+// type features find nothing.
+func (cols csvColumns) missingFeatures() []string {
+	var missing []string
+	if !cols.has(colLat, colLong) && !cols.has(colEasting, colNorthing) {
+		missing = append(missing, "no lat/long or gridgb1e/gridgb1n columns: places have no location, so grid reference, "+
+			"coordinate, nearby, distance, polygon and tile searches, and Pelias focus, find nothing")
+	}
+	var areas []string
+	for _, name := range []string{colCountry, colRegion, colCounty, colLAD, colNPark} {
+		if !cols.has(name) {
+			areas = append(areas, name)
+		}
+	}
+	switch len(areas) {
+	case 0:
+	case 1:
+		missing = append(missing, fmt.Sprintf("no %s column: its area filter finds nothing", areas[0]))
+	default:
+		missing = append(missing, fmt.Sprintf("no %s columns: their area filters find nothing", strings.Join(areas, ", ")))
+	}
+	if !cols.has(colType) {
+		missing = append(missing, "no descnm column: places have no type, so the type filter finds nothing and blended ranking is relevancy alone")
+	}
+	return missing
+}
+
+func (cols csvColumns) float(rec []string, name string) (float64, error) {
+	value := cols.get(rec, name)
+	if value == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value: %w", name, err)
+	}
+	return f, nil
+}
+
+func LoadCSV(filename string, action func(place *Place) error) (int, error) {
 	log.Printf("Loading data from: %s", filename)
 	file, err := os.Open(filename)
 	if err != nil {
@@ -35,6 +116,7 @@ func LoadCSV(filename string, action func(location string, score float64) error)
 	csvReader := csv.NewReader(gzReader)
 	csvReader.FieldsPerRecord = -1 // Allow variable number of fields
 	count := 0
+	cols := csvColumns{}
 
 	for {
 		count++
@@ -46,8 +128,16 @@ func LoadCSV(filename string, action func(location string, score float64) error)
 			return 0, fmt.Errorf("failed to read CSV record on line %d: %w", count, err)
 		}
 
-		// Skip header
+		// Header: name & relevancy are positional, any extras are by name
 		if count == 1 {
+			for idx, name := range rec {
+				if idx >= 2 {
+					cols[strings.ToLower(strings.TrimSpace(name))] = idx
+				}
+			}
+			for _, missing := range cols.missingFeatures() {
+				log.Printf("WARNING: the data file has %s. See the README's \"Optional columns\".", missing)
+			}
 			continue
 		}
 
@@ -60,10 +150,78 @@ func LoadCSV(filename string, action func(location string, score float64) error)
 			return 0, fmt.Errorf("invalid score value on line %d: %w", count, err)
 		}
 
-		if err := action(name, score); err != nil {
+		place := &Place{
+			ID:             cols.get(rec, colID),
+			Name:           name,
+			Relevancy:      score,
+			Grid1km:        cols.get(rec, colGrid1km),
+			Type:           NormalizePlaceType(cols.get(rec, colType)),
+			Country:        cols.get(rec, colCountry),
+			Region:         cols.get(rec, colRegion),
+			County:         cols.get(rec, colCounty),
+			LocalAuthority: cols.get(rec, colLAD),
+			NationalPark:   cols.get(rec, colNPark),
+		}
+		if err := cols.parseLocation(rec, place); err != nil {
+			return 0, fmt.Errorf("invalid location on line %d: %w", count, err)
+		}
+
+		if err := action(place); err != nil {
 			return 0, fmt.Errorf("failed to action (%s, %f): %w", name, score, err)
 		}
 	}
 
 	return count, nil
 }
+
+// parseLocation fills in whichever of lat/long and eastings/northings are
+// missing from the record by converting from the other.
+func (cols csvColumns) parseLocation(rec []string, place *Place) error {
+	var err error
+	if place.Lat, err = cols.float(rec, colLat); err != nil {
+		return err
+	}
+	if place.Long, err = cols.float(rec, colLong); err != nil {
+		return err
+	}
+	if place.Easting, err = cols.float(rec, colEasting); err != nil {
+		return err
+	}
+	if place.Northing, err = cols.float(rec, colNorthing); err != nil {
+		return err
+	}
+
+	hasGrid := place.Easting != 0 || place.Northing != 0
+	switch {
+	case place.HasLocation() && !hasGrid:
+		easting, northing := WGS84ToOSGrid(place.Lat, place.Long)
+		place.Easting, place.Northing = math.Round(easting), math.Round(northing)
+	case !place.HasLocation() && hasGrid:
+		place.Lat, place.Long = OSGridToWGS84(place.Easting, place.Northing)
+	}
+
+	if place.HasLocation() {
+		place.Geohash = EncodeGeohash(place.Lat, place.Long, GeohashPrecision)
+	}
+	return nil
+}
+
+// FileVersion identifies a data file by a short hash of its contents, so
+// responses can report which dataset answered.
+func FileVersion(filename string) (string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Printf("Error closing file: %v", err)
+		}
+	}()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))[:12], nil
+}
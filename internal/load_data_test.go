@@ -59,6 +59,53 @@ Luton,0.8
 		if results[0].Name != "Luton" {
 			t.Errorf("expected Luton, got %s", results[0].Name)
 		}
+		if !strings.HasPrefix(trie.Version(), "sha256:") || len(trie.Version()) != 19 {
+			t.Errorf("expected a short sha256 dataset version, got %q", trie.Version())
+		}
+	})
+
+	t.Run("optional location columns", func(t *testing.T) {
+		content := `name,relevancy,lat,long,gridgb1e,gridgb1n,grid1km
+London,1.0,51.5072,-0.1276,,,
+Oxford,0.9,,,451300,206200,SP5106
+Luton,0.8,,,,,
+`
+		path := createTestGzipFile(t, content)
+		trie, err := PopulateFrom(path, 100)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		places := trie.Places()
+		if len(places) != 3 {
+			t.Fatalf("expected 3 places, got %d", len(places))
+		}
+		if places[0].Easting == 0 || places[0].GridRef() != "TQ 300 803" {
+			t.Errorf("expected London grid ref to be derived from lat/long, got %q", places[0].GridRef())
+		}
+		if places[0].Geohash != "gcpvj0e54" {
+			t.Errorf("expected London geohash to be computed at load time, got %q", places[0].Geohash)
+		}
+		if !places[1].HasLocation() {
+			t.Errorf("expected Oxford lat/long to be derived from eastings & northings")
+		}
+		if places[2].HasLocation() || places[2].GridRef() != "" {
+			t.Errorf("expected Luton to have no location")
+		}
+	})
+
+	t.Run("invalid location column", func(t *testing.T) {
+		content := `name,relevancy,lat,long
+London,1.0,north,-0.1276
+`
+		path := createTestGzipFile(t, content)
+		_, err := PopulateFrom(path, 100)
+		if err == nil {
+			t.Fatal("expected an error for invalid lat, got nil")
+		}
+		if !strings.Contains(err.Error(), "invalid location on line 2") {
+			t.Errorf("expected error to contain 'invalid location on line 2', got %v", err)
+		}
 	})
 
 	t.Run("file not found", func(t *testing.T) {
@@ -157,3 +204,16 @@ Paris
 		}
 	})
 }
+
+func TestMissingFeatures(t *testing.T) {
+	names := csvColumns{}
+	if missing := names.missingFeatures(); len(missing) != 3 {
+		t.Errorf("expected the location, area and type features to be missing, got %q", missing)
+	}
+
+	gridded := csvColumns{colEasting: 2, colNorthing: 3, colCountry: 4, colRegion: 5, colCounty: 6, colLAD: 7, colType: 8}
+	missing := gridded.missingFeatures()
+	if len(missing) != 1 || !strings.Contains(missing[0], "no npark23nm column") {
+		t.Errorf("expected only the national park filter to be missing, got %q", missing)
+	}
+}
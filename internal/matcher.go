@@ -0,0 +1,154 @@
+package internal
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// How a name was matched, from most to least certain
+const (
+	MatchExact      = "exact"      // the name, ignoring case
+	MatchNormalized = "normalized" // the name, ignoring accents, punctuation and abbreviations
+	MatchFuzzy      = "fuzzy"      // the name, allowing for typos
+	MatchNone       = "none"
+)
+
+// The score for each kind of match, before allowing for relevancy. A fuzzy
+// match scores up to matchFuzzyScore, less for each edit.
+const (
+	matchExactScore      = 1.0
+	matchNormalizedScore = 0.9
+	matchFuzzyScore      = 0.8
+
+	// how much of the score comes from the place's relevancy, so that the
+	// better known of two places with the same name comes first
+	matchRelevancyWeight = 0.1
+)
+
+// normalizedAbbreviations maps words onto the form used when normalizing
+var normalizedAbbreviations = map[string]string{
+	"saint": "st",
+	"&":     "and",
+}
+
+// Match is a candidate for a name, scored from 0 to 1
+type Match struct {
+	Place *Place
+	Type  string
+	Score float64
+}
+
+// Matcher finds the places a free-text name most likely refers to, as when
+// reconciling a column of place names against the gazetteer.
+type Matcher struct {
+	trie       *Trie
+	normalized map[string][]*Place
+}
+
+// NewMatcher indexes the trie's places by their normalized names.
+func NewMatcher(trie *Trie) *Matcher {
+	m := &Matcher{trie: trie, normalized: make(map[string][]*Place)}
+	for _, place := range trie.Places() {
+		key := NormalizeName(place.Name)
+		m.normalized[key] = append(m.normalized[key], place)
+	}
+	return m
+}
+
+// NormalizeName folds the differences in how a place name might be written:
+// case, accents, punctuation, spacing and common abbreviations, so that
+// "St. Mary's-on-Sea" and "Saint Marys on Sea" are the same.
+func NormalizeName(name string) string {
+	stripped, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), name)
+	if err != nil {
+		stripped = name
+	}
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(stripped) {
+		switch {
+		case r == '\'' || r == '’' || r == '.':
+			// dropped, so "St. Mary's" becomes "st marys"
+		case r == '&' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune(' ')
+		}
+	}
+
+	words := strings.Fields(b.String())
+	for i, word := range words {
+		if abbreviation, ok := normalizedAbbreviations[word]; ok {
+			words[i] = abbreviation
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// Match returns up to limit candidates for the name which are accepted by
+// the predicate, best first. Exact and normalized matches are looked for
+// first; only if there are none is the (slower) fuzzy search made.
+func (m *Matcher) Match(name string, limit int, accept func(*Place) bool) []Match {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" || limit <= 0 {
+		return []Match{}
+	}
+	accepted := func(place *Place) bool {
+		return accept == nil || accept(place)
+	}
+
+	var matches []Match
+	seen := make(map[*Place]bool)
+	for _, place := range m.trie.FindByName(name) {
+		if accepted(place) {
+			seen[place] = true
+			matches = append(matches, newMatch(place, MatchExact, matchExactScore))
+		}
+	}
+	for _, place := range m.normalized[NormalizeName(name)] {
+		if !seen[place] && accepted(place) {
+			matches = append(matches, newMatch(place, MatchNormalized, matchNormalizedScore))
+		}
+	}
+
+	if len(matches) == 0 {
+		length := utf8.RuneCountInString(name)
+		opts := FuzzyOptions{MaxEdits: maxMatchEdits(length), PrefixLength: 1, Transpositions: true, WholeName: true}
+		if opts.MaxEdits > 0 {
+			for _, fuzzy := range m.trie.FindFuzzy(name, opts, limit, accept) {
+				longest := max(length, utf8.RuneCountInString(fuzzy.Place.Name))
+				score := matchFuzzyScore * (1 - float64(fuzzy.Edits)/float64(longest))
+				matches = append(matches, newMatch(fuzzy.Place, MatchFuzzy, score))
+			}
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a, b Match) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return matches[:min(limit, len(matches))]
+}
+
+func newMatch(place *Place, matchType string, score float64) Match {
+	return Match{Place: place, Type: matchType, Score: score * (1 - matchRelevancyWeight + matchRelevancyWeight*place.Relevancy)}
+}
+
+// maxMatchEdits allows more typos in longer names, as Elasticsearch's AUTO
+// fuzziness does: none in the shortest, where any edit makes another name.
+func maxMatchEdits(length int) int {
+	switch {
+	case length < 3:
+		return 0
+	case length < 6:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"London", "london"},
+		{"  Newcastle   upon  Tyne ", "newcastle upon tyne"},
+		{"Stratford-upon-Avon", "stratford upon avon"},
+		{"St. Mary's", "st marys"},
+		{"Saint Mary’s", "st marys"},
+		{"Ynys Môn", "ynys mon"},
+		{"Brighton & Hove", "brighton and hove"},
+		{"A' Chill", "a chill"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeName(tt.name); got != tt.expected {
+			t.Errorf("NormalizeName(%q) = %q, want %q", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	trie := NewTrie(10)
+	for _, place := range []*Place{
+		{Name: "Newport", Relevancy: 0.8, Country: "Wales"},
+		{Name: "Newport", Relevancy: 0.5, Country: "England"},
+		{Name: "Newport Pagnell", Relevancy: 0.4, Country: "England"},
+		{Name: "St Albans", Relevancy: 0.7, Country: "England"},
+		{Name: "Ynys Môn", Relevancy: 0.6, Country: "Wales"},
+		{Name: "Stratford-upon-Avon", Relevancy: 0.7, Country: "England"},
+	} {
+		trie.Insert(place)
+	}
+	matcher := NewMatcher(trie)
+
+	t.Run("exact", func(t *testing.T) {
+		matches := matcher.Match("newport", 5, nil)
+		if len(matches) != 2 {
+			t.Fatalf("expected both Newports, got %d matches", len(matches))
+		}
+		if matches[0].Type != MatchExact || matches[0].Place.Country != "Wales" {
+			t.Errorf("expected the more relevant Newport first, got %+v", matches[0])
+		}
+		if matches[0].Score <= matches[1].Score || matches[0].Score > 1 {
+			t.Errorf("expected descending scores of at most 1, got %f then %f", matches[0].Score, matches[1].Score)
+		}
+	})
+
+	t.Run("normalized", func(t *testing.T) {
+		for query, expected := range map[string]string{
+			"Saint Albans":        "St Albans",
+			"st. albans":          "St Albans",
+			"Ynys Mon":            "Ynys Môn",
+			"Stratford upon Avon": "Stratford-upon-Avon",
+		} {
+			matches := matcher.Match(query, 5, nil)
+			if len(matches) != 1 || matches[0].Place.Name != expected || matches[0].Type != MatchNormalized {
+				t.Errorf("%s: expected a normalized match for %s, got %+v", query, expected, matches)
+			}
+		}
+	})
+
+	t.Run("fuzzy", func(t *testing.T) {
+		matches := matcher.Match("Nweport Pagnell", 5, nil)
+		if len(matches) != 1 || matches[0].Place.Name != "Newport Pagnell" || matches[0].Type != MatchFuzzy {
+			t.Fatalf("expected a fuzzy match for Newport Pagnell, got %+v", matches)
+		}
+		if matches[0].Score >= matchFuzzyScore {
+			t.Errorf("expected a fuzzy match to score less than %f, got %f", matchFuzzyScore, matches[0].Score)
+		}
+	})
+
+	t.Run("none", func(t *testing.T) {
+		for _, query := range []string{"Paris", "", "Nx"} {
+			if matches := matcher.Match(query, 5, nil); len(matches) != 0 {
+				t.Errorf("%q: expected no matches, got %+v", query, matches)
+			}
+		}
+	})
+
+	t.Run("filter and limit", func(t *testing.T) {
+		filter := PlaceFilter{Country: "England"}
+		matches := matcher.Match("Newport", 5, filter.Predicate())
+		if len(matches) != 1 || matches[0].Place.Country != "England" {
+			t.Errorf("expected only the English Newport, got %+v", matches)
+		}
+		if matches := matcher.Match("Newport", 1, nil); len(matches) != 1 {
+			t.Errorf("expected 1 match, got %d", len(matches))
+		}
+	})
+}
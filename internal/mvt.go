@@ -0,0 +1,189 @@
+package internal
+
+import (
+	"math"
+	"sort"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Mapbox Vector Tile (v2.1) encoding for point features. See
+// https://github.com/mapbox/vector-tile-spec/tree/master/2.1 - the protobuf
+// messages are small enough to write directly with protowire.
+
+const (
+	TileExtent  = 4096
+	TileBuffer  = 64 // extent units either side, so labels aren't clipped at tile edges
+	MaxTileZoom = 18
+)
+
+// field numbers from vector_tile.proto
+const (
+	tileLayers      = 3
+	layerName       = 1
+	layerFeatures   = 2
+	layerKeys       = 3
+	layerValues     = 4
+	layerExtent     = 5
+	layerVersion    = 15
+	featureTags     = 2
+	featureType     = 3
+	featureGeometry = 4
+	valueString     = 1
+	valueDouble     = 3
+	geomTypePoint   = 1
+	cmdMoveTo       = 1
+)
+
+type TileCoord struct {
+	Z, X, Y int
+}
+
+func (t TileCoord) Valid() bool {
+	if t.Z < 0 || t.Z > MaxTileZoom {
+		return false
+	}
+	n := 1 << t.Z
+	return t.X >= 0 && t.X < n && t.Y >= 0 && t.Y < n
+}
+
+// Bounds returns the WGS84 bounding box of the tile, including the buffer.
+func (t TileCoord) Bounds() (minLat, minLong, maxLat, maxLong float64) {
+	buffer := float64(TileBuffer) / TileExtent
+	minLong, maxLat = tileToLongLat(t.Z, float64(t.X)-buffer, float64(t.Y)-buffer)
+	maxLong, minLat = tileToLongLat(t.Z, float64(t.X+1)+buffer, float64(t.Y+1)+buffer)
+	return minLat, minLong, maxLat, maxLong
+}
+
+func tileToLongLat(z int, x, y float64) (float64, float64) {
+	n := math.Exp2(float64(z))
+	long := x/n*360 - 180
+	lat := math.Atan(math.Sinh(math.Pi*(1-2*y/n))) * 180 / math.Pi
+	return long, lat
+}
+
+// project returns the position of a point within the tile, in extent units
+func (t TileCoord) project(lat, long float64) (int64, int64) {
+	n := math.Exp2(float64(t.Z))
+	latRad := toRadians(lat)
+	x := (long + 180) / 360 * n
+	y := (1 - math.Log(math.Tan(latRad)+1/math.Cos(latRad))/math.Pi) / 2 * n
+	return int64(math.Round((x - float64(t.X)) * TileExtent)), int64(math.Round((y - float64(t.Y)) * TileExtent))
+}
+
+// MinZoom is the lowest zoom level a place is shown at: the most relevant
+// places appear from zoom 2, the least relevant only from zoom 14.
+func MinZoom(relevancy float64) int {
+	return int(math.Round(14 - 12*math.Max(0, math.Min(1, relevancy))))
+}
+
+// EncodeTile renders the places visible at the tile's zoom level as a single
+// layer of point features, each with id, name, relevancy and type attributes.
+// At most maxFeatures places are included, most relevant first.
+func EncodeTile(layer string, tile TileCoord, places []*Place, maxFeatures int) []byte {
+	visible := make([]*Place, 0, len(places))
+	for _, place := range places {
+		if place.HasLocation() && MinZoom(place.Relevancy) <= tile.Z {
+			visible = append(visible, place)
+		}
+	}
+	sort.SliceStable(visible, func(i, j int) bool {
+		return lessRelevant(visible[j], visible[i])
+	})
+	visible = visible[:min(maxFeatures, len(visible))]
+
+	if len(visible) == 0 {
+		return []byte{}
+	}
+
+	enc := newLayerEncoder()
+	var features []byte
+	for _, place := range visible {
+		var tags []byte
+		tags = enc.tag(tags, "id", stringValue(place.ID))
+		tags = enc.tag(tags, "name", stringValue(place.Name))
+		tags = enc.tag(tags, "relevancy", doubleValue(place.Relevancy))
+		if place.Type != "" {
+			tags = enc.tag(tags, "type", stringValue(place.Type))
+		}
+
+		x, y := tile.project(place.Lat, place.Long)
+		var geom []byte
+		geom = protowire.AppendVarint(geom, uint64(cmdMoveTo&0x7|1<<3))
+		geom = protowire.AppendVarint(geom, protowire.EncodeZigZag(x))
+		geom = protowire.AppendVarint(geom, protowire.EncodeZigZag(y))
+
+		var feature []byte
+		feature = protowire.AppendTag(feature, featureTags, protowire.BytesType)
+		feature = protowire.AppendBytes(feature, tags)
+		feature = protowire.AppendTag(feature, featureType, protowire.VarintType)
+		feature = protowire.AppendVarint(feature, geomTypePoint)
+		feature = protowire.AppendTag(feature, featureGeometry, protowire.BytesType)
+		feature = protowire.AppendBytes(feature, geom)
+
+		features = protowire.AppendTag(features, layerFeatures, protowire.BytesType)
+		features = protowire.AppendBytes(features, feature)
+	}
+
+	var msg []byte
+	msg = protowire.AppendTag(msg, layerVersion, protowire.VarintType)
+	msg = protowire.AppendVarint(msg, 2)
+	msg = protowire.AppendTag(msg, layerName, protowire.BytesType)
+	msg = protowire.AppendString(msg, layer)
+	msg = append(msg, features...)
+	for _, key := range enc.keys {
+		msg = protowire.AppendTag(msg, layerKeys, protowire.BytesType)
+		msg = protowire.AppendString(msg, key)
+	}
+	for _, value := range enc.values {
+		msg = protowire.AppendTag(msg, layerValues, protowire.BytesType)
+		msg = protowire.AppendBytes(msg, value)
+	}
+	msg = protowire.AppendTag(msg, layerExtent, protowire.VarintType)
+	msg = protowire.AppendVarint(msg, TileExtent)
+
+	var out []byte
+	out = protowire.AppendTag(out, tileLayers, protowire.BytesType)
+	return protowire.AppendBytes(out, msg)
+}
+
+// layerEncoder de-duplicates the keys & values shared by a layer's features
+type layerEncoder struct {
+	keys       []string
+	keyIndex   map[string]int
+	values     [][]byte
+	valueIndex map[string]int
+}
+
+func newLayerEncoder() *layerEncoder {
+	return &layerEncoder{keyIndex: map[string]int{}, valueIndex: map[string]int{}}
+}
+
+func (enc *layerEncoder) tag(tags []byte, key string, value []byte) []byte {
+	k, ok := enc.keyIndex[key]
+	if !ok {
+		k = len(enc.keys)
+		enc.keys = append(enc.keys, key)
+		enc.keyIndex[key] = k
+	}
+
+	v, ok := enc.valueIndex[string(value)]
+	if !ok {
+		v = len(enc.values)
+		enc.values = append(enc.values, value)
+		enc.valueIndex[string(value)] = v
+	}
+
+	tags = protowire.AppendVarint(tags, uint64(k))
+	return protowire.AppendVarint(tags, uint64(v))
+}
+
+func stringValue(s string) []byte {
+	b := protowire.AppendTag(nil, valueString, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+func doubleValue(f float64) []byte {
+	b := protowire.AppendTag(nil, valueDouble, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, math.Float64bits(f))
+}
@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// decodeTile unpacks just enough of a tile to check the layer's contents
+func decodeTile(t *testing.T, data []byte) (name string, features int, keys []string) {
+	t.Helper()
+	num, typ, n := protowire.ConsumeTag(data)
+	if num != tileLayers || typ != protowire.BytesType {
+		t.Fatalf("expected a layer, got field %d type %d", num, typ)
+	}
+	layer, _ := protowire.ConsumeBytes(data[n:])
+
+	for len(layer) > 0 {
+		num, typ, n := protowire.ConsumeTag(layer)
+		layer = layer[n:]
+		n = protowire.ConsumeFieldValue(num, typ, layer)
+		switch num {
+		case layerName:
+			name, _ = protowire.ConsumeString(layer)
+		case layerFeatures:
+			features++
+		case layerKeys:
+			key, _ := protowire.ConsumeString(layer)
+			keys = append(keys, key)
+		}
+		layer = layer[n:]
+	}
+	return name, features, keys
+}
+
+func TestMVT(t *testing.T) {
+	places := []*Place{
+		{ID: "1", Name: "London", Relevancy: 1.0, Lat: 51.5072, Long: -0.1276, Type: PlaceTypeCity},
+		{ID: "2", Name: "Croydon", Relevancy: 0.5, Lat: 51.3762, Long: -0.0982},
+		{ID: "3", Name: "Nowhere", Relevancy: 1.0},
+	}
+
+	t.Run("tile validity", func(t *testing.T) {
+		valid := []TileCoord{{0, 0, 0}, {10, 511, 340}, {MaxTileZoom, 0, 0}}
+		invalid := []TileCoord{{-1, 0, 0}, {1, 2, 0}, {3, 0, -1}, {MaxTileZoom + 1, 0, 0}}
+		for _, tile := range valid {
+			if !tile.Valid() {
+				t.Errorf("expected %v to be valid", tile)
+			}
+		}
+		for _, tile := range invalid {
+			if tile.Valid() {
+				t.Errorf("expected %v to be invalid", tile)
+			}
+		}
+	})
+
+	t.Run("tile bounds", func(t *testing.T) {
+		minLat, minLong, maxLat, maxLong := TileCoord{Z: 1, X: 0, Y: 0}.Bounds()
+		if minLong > -180 || maxLong < 0 || maxLat < 85 || minLat > 0 {
+			t.Errorf("unexpected bounds for tile 1/0/0: (%f, %f) - (%f, %f)", minLat, minLong, maxLat, maxLong)
+		}
+	})
+
+	t.Run("project into tile", func(t *testing.T) {
+		x, y := TileCoord{}.project(0, 0)
+		if x != TileExtent/2 || y != TileExtent/2 {
+			t.Errorf("expected (0, 0) at the centre of tile 0/0/0, got (%d, %d)", x, y)
+		}
+	})
+
+	t.Run("min zoom by relevancy", func(t *testing.T) {
+		if MinZoom(1.0) != 2 || MinZoom(0.5) != 8 || MinZoom(0) != 14 || MinZoom(-1) != 14 {
+			t.Errorf("unexpected min zooms: %d, %d, %d", MinZoom(1.0), MinZoom(0.5), MinZoom(0))
+		}
+	})
+
+	t.Run("low zoom only has the most relevant places", func(t *testing.T) {
+		name, features, keys := decodeTile(t, EncodeTile("places", TileCoord{Z: 5, X: 15, Y: 10}, places, 100))
+		if name != "places" {
+			t.Errorf("expected layer name 'places', got %q", name)
+		}
+		if features != 1 {
+			t.Errorf("expected 1 feature, got %d", features)
+		}
+		expectedKeys := []string{"id", "name", "relevancy", "type"}
+		if len(keys) != len(expectedKeys) {
+			t.Fatalf("expected keys %v, got %v", expectedKeys, keys)
+		}
+	})
+
+	t.Run("high zoom has all located places", func(t *testing.T) {
+		_, features, _ := decodeTile(t, EncodeTile("places", TileCoord{Z: 9, X: 255, Y: 170}, places, 100))
+		if features != 2 {
+			t.Errorf("expected 2 features, got %d", features)
+		}
+	})
+
+	t.Run("features are capped", func(t *testing.T) {
+		_, features, _ := decodeTile(t, EncodeTile("places", TileCoord{Z: 9, X: 255, Y: 170}, places, 1))
+		if features != 1 {
+			t.Errorf("expected 1 feature, got %d", features)
+		}
+	})
+
+	t.Run("empty tile", func(t *testing.T) {
+		if data := EncodeTile("places", TileCoord{Z: 3}, nil, 100); len(data) != 0 {
+			t.Errorf("expected an empty tile, got %d bytes", len(data))
+		}
+	})
+
+}
@@ -0,0 +1,245 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Conversions between WGS84 latitude/longitude and Ordnance Survey National
+// Grid eastings/northings (OSGB36 datum, Airy 1830 ellipsoid). The formulae
+// follow the OS "A guide to coordinate systems in Great Britain", using a
+// 7-parameter Helmert transform between datums which is accurate to within
+// a few metres - more than enough for finding nearby place names.
+
+type ellipsoid struct {
+	a, b float64
+}
+
+func (el ellipsoid) e2() float64 {
+	return 1 - (el.b*el.b)/(el.a*el.a)
+}
+
+var (
+	airy1830 = ellipsoid{a: 6377563.396, b: 6356256.909}
+	wgs84    = ellipsoid{a: 6378137, b: 6356752.314245}
+)
+
+// National Grid true origin and scale factor
+const (
+	gridF0   = 0.9996012717
+	gridLat0 = 49 * math.Pi / 180
+	gridLon0 = -2 * math.Pi / 180
+	gridN0   = -100000.0
+	gridE0   = 400000.0
+)
+
+type helmert struct {
+	tx, ty, tz float64 // metres
+	rx, ry, rz float64 // arc-seconds
+	s          float64 // ppm
+}
+
+var wgs84ToOSGB36 = helmert{
+	tx: -446.448, ty: 125.157, tz: -542.060,
+	rx: -0.1502, ry: -0.2470, rz: -0.8421,
+	s: 20.4894,
+}
+
+func (h helmert) inverse() helmert {
+	return helmert{tx: -h.tx, ty: -h.ty, tz: -h.tz, rx: -h.rx, ry: -h.ry, rz: -h.rz, s: -h.s}
+}
+
+// OSGridToWGS84 converts National Grid eastings & northings (metres) to
+// WGS84 latitude and longitude (degrees).
+func OSGridToWGS84(easting, northing float64) (float64, float64) {
+	lat, lon := gridToLatLon(easting, northing)
+	lat, lon = convertDatum(lat, lon, airy1830, wgs84ToOSGB36.inverse(), wgs84)
+	return lat * 180 / math.Pi, lon * 180 / math.Pi
+}
+
+// WGS84ToOSGrid converts WGS84 latitude and longitude (degrees) to National
+// Grid eastings & northings (metres).
+func WGS84ToOSGrid(lat, long float64) (float64, float64) {
+	phi, lambda := convertDatum(lat*math.Pi/180, long*math.Pi/180, wgs84, wgs84ToOSGB36, airy1830)
+	return latLonToGrid(phi, lambda)
+}
+
+func meridionalArc(phi float64) float64 {
+	a, b := airy1830.a, airy1830.b
+	n := (a - b) / (a + b)
+	n2, n3 := n*n, n*n*n
+	dPhi, sPhi := phi-gridLat0, phi+gridLat0
+
+	ma := (1 + n + 5.0/4*n2 + 5.0/4*n3) * dPhi
+	mb := (3*n + 3*n2 + 21.0/8*n3) * math.Sin(dPhi) * math.Cos(sPhi)
+	mc := (15.0/8*n2 + 15.0/8*n3) * math.Sin(2*dPhi) * math.Cos(2*sPhi)
+	md := 35.0 / 24 * n3 * math.Sin(3*dPhi) * math.Cos(3*sPhi)
+	return b * gridF0 * (ma - mb + mc - md)
+}
+
+func radiiOfCurvature(phi float64) (nu, rho, eta2 float64) {
+	a, e2 := airy1830.a, airy1830.e2()
+	sin2 := math.Sin(phi) * math.Sin(phi)
+	nu = a * gridF0 / math.Sqrt(1-e2*sin2)
+	rho = a * gridF0 * (1 - e2) / math.Pow(1-e2*sin2, 1.5)
+	eta2 = nu/rho - 1
+	return nu, rho, eta2
+}
+
+func latLonToGrid(phi, lambda float64) (float64, float64) {
+	nu, rho, eta2 := radiiOfCurvature(phi)
+	sin, cos, tan := math.Sin(phi), math.Cos(phi), math.Tan(phi)
+	cos3, cos5 := cos*cos*cos, cos*cos*cos*cos*cos
+	tan2, tan4 := tan*tan, tan*tan*tan*tan
+
+	i := meridionalArc(phi) + gridN0
+	ii := nu / 2 * sin * cos
+	iii := nu / 24 * sin * cos3 * (5 - tan2 + 9*eta2)
+	iiiA := nu / 720 * sin * cos5 * (61 - 58*tan2 + tan4)
+	iv := nu * cos
+	v := nu / 6 * cos3 * (nu/rho - tan2)
+	vi := nu / 120 * cos5 * (5 - 18*tan2 + tan4 + 14*eta2 - 58*tan2*eta2)
+
+	dL := lambda - gridLon0
+	dL2, dL3 := dL*dL, dL*dL*dL
+	northing := i + ii*dL2 + iii*dL2*dL2 + iiiA*dL3*dL3
+	easting := gridE0 + iv*dL + v*dL3 + vi*dL3*dL2
+	return easting, northing
+}
+
+func gridToLatLon(easting, northing float64) (float64, float64) {
+	phi := gridLat0
+	m := 0.0
+	for {
+		phi += (northing - gridN0 - m) / (airy1830.a * gridF0)
+		m = meridionalArc(phi)
+		if math.Abs(northing-gridN0-m) < 0.00001 {
+			break
+		}
+	}
+
+	nu, rho, eta2 := radiiOfCurvature(phi)
+	tan, sec := math.Tan(phi), 1/math.Cos(phi)
+	tan2, tan4, tan6 := tan*tan, math.Pow(tan, 4), math.Pow(tan, 6)
+	nu3, nu5, nu7 := math.Pow(nu, 3), math.Pow(nu, 5), math.Pow(nu, 7)
+
+	vii := tan / (2 * rho * nu)
+	viii := tan / (24 * rho * nu3) * (5 + 3*tan2 + eta2 - 9*tan2*eta2)
+	ix := tan / (720 * rho * nu5) * (61 + 90*tan2 + 45*tan4)
+	x := sec / nu
+	xi := sec / (6 * nu3) * (nu/rho + 2*tan2)
+	xii := sec / (120 * nu5) * (5 + 28*tan2 + 24*tan4)
+	xiiA := sec / (5040 * nu7) * (61 + 662*tan2 + 1320*tan4 + 720*tan6)
+
+	dE := easting - gridE0
+	dE2, dE3 := dE*dE, dE*dE*dE
+	lat := phi - vii*dE2 + viii*dE2*dE2 - ix*dE3*dE3
+	lon := gridLon0 + x*dE - xi*dE3 + xii*dE3*dE2 - xiiA*dE3*dE2*dE2
+	return lat, lon
+}
+
+func convertDatum(phi, lambda float64, from ellipsoid, t helmert, to ellipsoid) (float64, float64) {
+	// to cartesian (height assumed zero)
+	e2 := from.e2()
+	nu := from.a / math.Sqrt(1-e2*math.Sin(phi)*math.Sin(phi))
+	x := nu * math.Cos(phi) * math.Cos(lambda)
+	y := nu * math.Cos(phi) * math.Sin(lambda)
+	z := (1 - e2) * nu * math.Sin(phi)
+
+	// apply helmert transform
+	s1 := t.s/1e6 + 1
+	rx := t.rx / 3600 * math.Pi / 180
+	ry := t.ry / 3600 * math.Pi / 180
+	rz := t.rz / 3600 * math.Pi / 180
+	x2 := t.tx + x*s1 - y*rz + z*ry
+	y2 := t.ty + x*rz + y*s1 - z*rx
+	z2 := t.tz - x*ry + y*rx + z*s1
+
+	// back to polar on the target ellipsoid
+	e2 = to.e2()
+	p := math.Sqrt(x2*x2 + y2*y2)
+	phi = math.Atan2(z2, p*(1-e2))
+	for range 10 {
+		nu = to.a / math.Sqrt(1-e2*math.Sin(phi)*math.Sin(phi))
+		next := math.Atan2(z2+e2*nu*math.Sin(phi), p)
+		if math.Abs(next-phi) < 1e-12 {
+			phi = next
+			break
+		}
+		phi = next
+	}
+	return phi, math.Atan2(y2, x2)
+}
+
+// gridLetters excludes 'I', as per the National Grid lettering scheme
+const gridLetters = "ABCDEFGHJKLMNOPQRSTUVWXYZ"
+
+// ParseGridRef parses an OS grid reference such as "SU 123 456", "TQ3080"
+// or "NN 16650 71250" and returns the easting & northing of the centre of
+// the referenced square.
+func ParseGridRef(ref string) (float64, float64, error) {
+	compact := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, ref)
+
+	if len(compact) < 4 {
+		return 0, 0, fmt.Errorf("grid reference too short: %q", ref)
+	}
+
+	l1 := strings.IndexByte(gridLetters, compact[0])
+	l2 := strings.IndexByte(gridLetters, compact[1])
+	if l1 < 0 || l2 < 0 {
+		return 0, 0, fmt.Errorf("invalid grid letters: %q", ref)
+	}
+
+	e100km := ((l1-2)%5)*5 + (l2 % 5)
+	n100km := (19 - (l1/5)*5) - (l2 / 5)
+	if l1 < 2 || e100km < 0 || e100km > 6 || n100km < 0 || n100km > 12 {
+		return 0, 0, fmt.Errorf("grid square out of range: %q", ref)
+	}
+
+	digits := compact[2:]
+	if len(digits)%2 != 0 || len(digits) > 10 {
+		return 0, 0, fmt.Errorf("grid reference must have an even number of digits (2-10): %q", ref)
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return 0, 0, fmt.Errorf("invalid grid reference digits: %q", ref)
+		}
+	}
+
+	half := len(digits) / 2
+	precision := math.Pow10(5 - half)
+	e, _ := strconv.Atoi(digits[:half])
+	n, _ := strconv.Atoi(digits[half:])
+
+	easting := float64(e100km)*100000 + float64(e)*precision + precision/2
+	northing := float64(n100km)*100000 + float64(n)*precision + precision/2
+	return easting, northing, nil
+}
+
+// FormatGridRef renders an easting & northing as a grid reference with the
+// given number of digits (2-10), e.g. "SU 123 456" for 6 digits.
+func FormatGridRef(easting, northing float64, digits int) string {
+	e100km := int(math.Floor(easting / 100000))
+	n100km := int(math.Floor(northing / 100000))
+	if e100km < 0 || e100km > 6 || n100km < 0 || n100km > 12 {
+		return ""
+	}
+
+	l1 := (19 - n100km) - (19-n100km)%5 + (e100km+10)/5
+	l2 := ((19-n100km)*5)%25 + e100km%5
+
+	half := digits / 2
+	precision := math.Pow10(5 - half)
+	e := int(math.Floor(math.Mod(easting, 100000) / precision))
+	n := int(math.Floor(math.Mod(northing, 100000) / precision))
+
+	return fmt.Sprintf("%c%c %0*d %0*d", gridLetters[l1], gridLetters[l2], half, e, half, n)
+}
@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOSGrid(t *testing.T) {
+	t.Run("parse grid references", func(t *testing.T) {
+		tests := []struct {
+			ref      string
+			easting  float64
+			northing float64
+		}{
+			{"TQ3080", 530500, 180500},
+			{"SU 123 456", 412350, 145650},
+			{"su123456", 412350, 145650},
+			{"NN 16650 71250", 216650.5, 771250.5},
+			{"HP 40 12", 440500, 1212500},
+		}
+
+		for _, tt := range tests {
+			e, n, err := ParseGridRef(tt.ref)
+			if err != nil {
+				t.Fatalf("%s: expected no error, got %v", tt.ref, err)
+			}
+			if e != tt.easting || n != tt.northing {
+				t.Errorf("%s: expected (%.1f, %.1f), got (%.1f, %.1f)", tt.ref, tt.easting, tt.northing, e, n)
+			}
+		}
+	})
+
+	t.Run("reject invalid grid references", func(t *testing.T) {
+		for _, ref := range []string{"", "SU", "SU1", "SU12345", "IX1234", "AA1234", "York", "SU12ab"} {
+			if _, _, err := ParseGridRef(ref); err == nil {
+				t.Errorf("%q: expected an error, got nil", ref)
+			}
+		}
+	})
+
+	t.Run("format grid references", func(t *testing.T) {
+		if ref := FormatGridRef(412350, 145650, 6); ref != "SU 123 456" {
+			t.Errorf("expected SU 123 456, got %s", ref)
+		}
+		if ref := FormatGridRef(530500, 180500, 4); ref != "TQ 30 80" {
+			t.Errorf("expected TQ 30 80, got %s", ref)
+		}
+		if ref := FormatGridRef(-1, 0, 6); ref != "" {
+			t.Errorf("expected empty string for out of range, got %s", ref)
+		}
+	})
+
+	t.Run("convert grid to WGS84", func(t *testing.T) {
+		// Nelson's Column, Trafalgar Square
+		lat, long := OSGridToWGS84(530027, 180407)
+		if math.Abs(lat-51.5077) > 0.0005 || math.Abs(long-(-0.1280)) > 0.0005 {
+			t.Errorf("expected approx (51.5077, -0.1280), got (%.5f, %.5f)", lat, long)
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		lat, long := 57.1497, -2.0943 // Aberdeen
+		e, n := WGS84ToOSGrid(lat, long)
+		lat2, long2 := OSGridToWGS84(e, n)
+		if math.Abs(lat-lat2) > 1e-6 || math.Abs(long-long2) > 1e-6 {
+			t.Errorf("expected (%f, %f) after round trip, got (%f, %f)", lat, long, lat2, long2)
+		}
+	})
+}
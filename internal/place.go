@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+)
+
+type Place struct {
+	ID        string // ONS place23cd, or generated from the rest of the row
+	Name      string
+	Relevancy float64
+	Lat       float64 // WGS84, zero when unknown
+	Long      float64 // WGS84, zero when unknown
+	Easting   float64 // OS National Grid, zero when unknown
+	Northing  float64 // OS National Grid, zero when unknown
+	Grid1km   string  // 1km grid reference as supplied in the data file
+	Geohash   string  // computed at load time, empty when there is no location
+	Type      string  // normalized from the ONS descnm, empty when unknown
+
+	// ONS administrative hierarchy, empty when unknown
+	Country        string // ctry23nm
+	Region         string // rgn23nm
+	County         string // cty23nm
+	LocalAuthority string // lad23nm
+	NationalPark   string // npark23nm
+
+	seq int // insertion order, used as a final tie-break
+}
+
+// generateID derives a stable identifier from everything known about the
+// place, for data files without a place23cd column. It depends only on the
+// row itself, not where it is in the file, so places keep their IDs when
+// the data is reordered or added to. Places with the same name are told
+// apart by their relevancy, location or area, whichever the file has.
+func generateID(place *Place) string {
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%s|%g|%.5f|%.5f|%s|%s|%s|%s|%s|%s|%s",
+		place.Name, place.Relevancy, place.Lat, place.Long, place.Grid1km, place.Type,
+		place.Country, place.Region, place.County, place.LocalAuthority, place.NationalPark)
+	return fmt.Sprintf("gen%016x", h.Sum64())
+}
+
+// HasLocation reports whether the place has coordinates; (0, 0) is in the
+// Gulf of Guinea so can safely be treated as "no location" for a UK gazetteer.
+func (p *Place) HasLocation() bool {
+	return p.Lat != 0 || p.Long != 0
+}
+
+// GridRef returns a 6-figure OS grid reference (100m precision) for the
+// place, falling back to the 1km reference in the data file if there are
+// no eastings & northings.
+func (p *Place) GridRef() string {
+	if p.Easting != 0 || p.Northing != 0 {
+		return FormatGridRef(p.Easting, p.Northing, 6)
+	}
+	return p.Grid1km
+}
+
+// lessRelevant orders places by relevancy, then shorter names first, then
+// earliest inserted first. It is a total order, so walking the trie always
+// yields places in the same sequence.
+func lessRelevant(a, b *Place) bool {
+	if a.Relevancy != b.Relevancy {
+		return a.Relevancy < b.Relevancy
+	}
+	if len(a.Name) != len(b.Name) {
+		return len(a.Name) > len(b.Name)
+	}
+	return a.seq > b.seq
+}
+
+// SortByRelevancy sorts the places in-place, most relevant first, using the
+// same ordering as the trie.
+func SortByRelevancy(places []*Place) {
+	sort.Slice(places, func(i, j int) bool {
+		return lessRelevant(places[j], places[i])
+	})
+}
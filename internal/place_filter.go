@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"slices"
+	"strings"
+)
+
+// PlaceFilter restricts results to part of the ONS administrative hierarchy.
+// Empty fields match anything; comparisons are case-insensitive.
+type PlaceFilter struct {
+	Country        string
+	Region         string
+	County         string
+	LocalAuthority string
+	NationalPark   string
+	Types          []string // canonical place types, any of which match
+	Geohash        string   // geohash prefix, i.e. the cell the place must be in
+}
+
+func (f PlaceFilter) IsEmpty() bool {
+	return f.Country == "" && f.Region == "" && f.County == "" &&
+		f.LocalAuthority == "" && f.NationalPark == "" && len(f.Types) == 0 &&
+		f.Geohash == ""
+}
+
+func (f PlaceFilter) Matches(place *Place) bool {
+	return matchesField(f.Country, place.Country) &&
+		matchesField(f.Region, place.Region) &&
+		matchesField(f.County, place.County) &&
+		matchesField(f.LocalAuthority, place.LocalAuthority) &&
+		matchesField(f.NationalPark, place.NationalPark) &&
+		(len(f.Types) == 0 || slices.Contains(f.Types, place.Type)) &&
+		strings.HasPrefix(place.Geohash, f.Geohash)
+}
+
+// Key is the filter in a canonical form, the same for any two filters which
+// match the same places.
+func (f PlaceFilter) Key() string {
+	types := slices.Clone(f.Types)
+	slices.Sort(types)
+	return strings.ToLower(strings.Join([]string{
+		f.Country, f.Region, f.County, f.LocalAuthority, f.NationalPark,
+		strings.Join(slices.Compact(types), ","), f.Geohash,
+	}, "|"))
+}
+
+// Predicate returns the filter as a function suitable for FindByPrefixFunc
+// and SpatialIndex.Nearest, or nil if the filter is empty.
+func (f PlaceFilter) Predicate() func(*Place) bool {
+	if f.IsEmpty() {
+		return nil
+	}
+	return f.Matches
+}
+
+func matchesField(want, value string) bool {
+	return want == "" || strings.EqualFold(want, value)
+}
@@ -0,0 +1,56 @@
+package internal
+
+import "testing"
+
+func TestPlaceFilter(t *testing.T) {
+	place := &Place{
+		Name:           "Keswick",
+		Country:        "England",
+		Region:         "North West",
+		County:         "Cumbria",
+		LocalAuthority: "Cumberland",
+		NationalPark:   "Lake District National Park",
+		Geohash:        "gcv2hk3p7",
+	}
+
+	tests := []struct {
+		name     string
+		filter   PlaceFilter
+		expected bool
+	}{
+		{"empty filter", PlaceFilter{}, true},
+		{"matching country", PlaceFilter{Country: "england"}, true},
+		{"mismatched country", PlaceFilter{Country: "Wales"}, false},
+		{"matching region & county", PlaceFilter{Region: "North West", County: "CUMBRIA"}, true},
+		{"mismatched local authority", PlaceFilter{Region: "North West", LocalAuthority: "Westmorland and Furness"}, false},
+		{"matching national park", PlaceFilter{NationalPark: "Lake District National Park"}, true},
+		{"matching geohash cell", PlaceFilter{Geohash: "gcv2"}, true},
+		{"mismatched geohash cell", PlaceFilter{Geohash: "gcpv"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(place); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("empty filter has no predicate", func(t *testing.T) {
+		if (PlaceFilter{}).Predicate() != nil {
+			t.Error("expected nil predicate for empty filter")
+		}
+	})
+
+	t.Run("key", func(t *testing.T) {
+		key := PlaceFilter{Country: "England", Types: []string{"town", "city", "town"}}.Key()
+		if same := (PlaceFilter{Country: "ENGLAND", Types: []string{"city", "town"}}).Key(); same != key {
+			t.Errorf("expected equivalent filters to share a key, got %q and %q", key, same)
+		}
+		for _, other := range []PlaceFilter{{}, {Country: "England"}, {County: "England", Types: []string{"city", "town"}}} {
+			if other.Key() == key {
+				t.Errorf("%+v: expected a different key to %q", other, key)
+			}
+		}
+	})
+}
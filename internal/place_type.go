@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"sort"
+	"strings"
+)
+
+// Canonical place types, as returned in results and accepted by filters
+const (
+	PlaceTypeCity     = "city"
+	PlaceTypeTown     = "town"
+	PlaceTypeVillage  = "village"
+	PlaceTypeSuburb   = "suburb"
+	PlaceTypeLocality = "locality"
+	PlaceTypeHamlet   = "hamlet"
+)
+
+// placeTypeAliases maps ONS descnm codes & descriptions onto a canonical
+// type. Anything not listed is lower-cased with spaces as underscores.
+var placeTypeAliases = map[string]string{
+	"city":     PlaceTypeCity,
+	"town":     PlaceTypeTown,
+	"village":  PlaceTypeVillage,
+	"suburb":   PlaceTypeSuburb,
+	"loc":      PlaceTypeLocality,
+	"locality": PlaceTypeLocality,
+	"hamlet":   PlaceTypeHamlet,
+	"bua":      "built_up_area",
+	"buasd":    "built_up_area_subdivision",
+	"par":      "parish",
+	"com":      "community",
+	"wd":       "ward",
+	"ced":      "electoral_division",
+	"npark":    "national_park",
+}
+
+// placeTypeWeights is the prominence of each place type used when blending
+// with relevancy; unlisted types sit in the middle.
+var placeTypeWeights = map[string]float64{
+	PlaceTypeCity:     1.0,
+	PlaceTypeTown:     0.8,
+	PlaceTypeVillage:  0.6,
+	PlaceTypeSuburb:   0.5,
+	PlaceTypeLocality: 0.4,
+	PlaceTypeHamlet:   0.3,
+}
+
+const (
+	defaultTypeWeight = 0.5
+	typeBlendFactor   = 0.3 // proportion of the blended score from the place type
+)
+
+func NormalizePlaceType(descnm string) string {
+	key := strings.ToLower(strings.TrimSpace(descnm))
+	if key == "" {
+		return ""
+	}
+	if alias, ok := placeTypeAliases[key]; ok {
+		return alias
+	}
+	return strings.Join(strings.Fields(key), "_")
+}
+
+// BlendedScore mixes the relevancy score with the prominence of the place type.
+func BlendedScore(place *Place) float64 {
+	weight, ok := placeTypeWeights[place.Type]
+	if !ok {
+		weight = defaultTypeWeight
+	}
+	return (1-typeBlendFactor)*place.Relevancy + typeBlendFactor*weight
+}
+
+// SortByBlendedScore re-ranks the places in-place, most prominent first.
+func SortByBlendedScore(places []*Place) {
+	sort.SliceStable(places, func(i, j int) bool {
+		return BlendedScore(places[i]) > BlendedScore(places[j])
+	})
+}
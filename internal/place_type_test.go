@@ -0,0 +1,47 @@
+package internal
+
+import "testing"
+
+func TestPlaceType(t *testing.T) {
+	t.Run("normalize descnm", func(t *testing.T) {
+		tests := map[string]string{
+			"LOC":          PlaceTypeLocality,
+			"Town":         PlaceTypeTown,
+			" city ":       PlaceTypeCity,
+			"BUASD":        "built_up_area_subdivision",
+			"Country Park": "country_park",
+			"":             "",
+		}
+		for descnm, expected := range tests {
+			if got := NormalizePlaceType(descnm); got != expected {
+				t.Errorf("%q: expected %q, got %q", descnm, expected, got)
+			}
+		}
+	})
+
+	t.Run("blended ranking favours more prominent types", func(t *testing.T) {
+		places := []*Place{
+			{Name: "Hamletton", Relevancy: 0.7, Type: PlaceTypeHamlet},
+			{Name: "Townley", Relevancy: 0.65, Type: PlaceTypeTown},
+			{Name: "Unknown", Relevancy: 0.6},
+		}
+		SortByBlendedScore(places)
+
+		expected := []string{"Townley", "Hamletton", "Unknown"}
+		for i, name := range expected {
+			if places[i].Name != name {
+				t.Errorf("position %d: expected %s, got %s", i, name, places[i].Name)
+			}
+		}
+	})
+
+	t.Run("filter by type", func(t *testing.T) {
+		filter := PlaceFilter{Types: []string{PlaceTypeCity, PlaceTypeTown}}
+		if !filter.Matches(&Place{Type: PlaceTypeTown}) {
+			t.Error("expected town to match")
+		}
+		if filter.Matches(&Place{Type: PlaceTypeHamlet}) {
+			t.Error("expected hamlet not to match")
+		}
+	})
+}
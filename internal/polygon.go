@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"errors"
+	"math"
+)
+
+// Position is a [long, lat] pair, in GeoJSON order
+type Position [2]float64
+
+// Polygon is a list of linear rings: the first is the exterior, the rest
+// are holes.
+type Polygon [][]Position
+
+// MultiPolygon is a union of polygons; a plain Polygon is a MultiPolygon
+// with a single member.
+type MultiPolygon []Polygon
+
+var ErrInvalidPolygon = errors.New("polygon rings must have at least 4 positions")
+
+func (mp MultiPolygon) Validate() error {
+	if len(mp) == 0 {
+		return ErrInvalidPolygon
+	}
+	for _, polygon := range mp {
+		if len(polygon) == 0 {
+			return ErrInvalidPolygon
+		}
+		for _, ring := range polygon {
+			if len(ring) < 4 {
+				return ErrInvalidPolygon
+			}
+		}
+	}
+	return nil
+}
+
+// Contains reports whether the point is inside any of the polygons (and
+// outside their holes).
+func (mp MultiPolygon) Contains(lat, long float64) bool {
+	for _, polygon := range mp {
+		if !ringContains(polygon[0], lat, long) {
+			continue
+		}
+		inHole := false
+		for _, hole := range polygon[1:] {
+			if ringContains(hole, lat, long) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+// ringContains uses the even-odd ray casting rule
+func ringContains(ring []Position, lat, long float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+		if (yi > lat) != (yj > lat) && long < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Bounds returns the bounding box of the exterior rings.
+func (mp MultiPolygon) Bounds() (minLat, minLong, maxLat, maxLong float64) {
+	minLat, minLong = math.Inf(1), math.Inf(1)
+	maxLat, maxLong = math.Inf(-1), math.Inf(-1)
+	for _, polygon := range mp {
+		for _, pos := range polygon[0] {
+			minLong, maxLong = min(minLong, pos[0]), max(maxLong, pos[0])
+			minLat, maxLat = min(minLat, pos[1]), max(maxLat, pos[1])
+		}
+	}
+	return minLat, minLong, maxLat, maxLong
+}
+
+// WithinShape returns the places inside the shape, using the grid cells to
+// pre-filter candidates to its bounding box.
+func (idx *SpatialIndex) WithinShape(shape MultiPolygon) []*Place {
+	candidates := idx.Within(shape.Bounds())
+	results := candidates[:0]
+	for _, place := range candidates {
+		if shape.Contains(place.Lat, place.Long) {
+			results = append(results, place)
+		}
+	}
+	return results
+}
@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPolygon(t *testing.T) {
+	// a square around central London with a hole over the City
+	square := Polygon{
+		{{-0.3, 51.3}, {0.1, 51.3}, {0.1, 51.6}, {-0.3, 51.6}, {-0.3, 51.3}},
+		{{-0.1, 51.5}, {-0.07, 51.5}, {-0.07, 51.52}, {-0.1, 51.52}, {-0.1, 51.5}},
+	}
+	oxford := Polygon{
+		{{-1.35, 51.7}, {-1.15, 51.7}, {-1.25, 51.8}, {-1.35, 51.7}},
+	}
+	shape := MultiPolygon{square, oxford}
+
+	t.Run("validate", func(t *testing.T) {
+		if err := shape.Validate(); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+		invalid := []MultiPolygon{{}, {Polygon{}}, {Polygon{{{0, 0}, {1, 1}, {0, 0}}}}}
+		for _, mp := range invalid {
+			if err := mp.Validate(); !errors.Is(err, ErrInvalidPolygon) {
+				t.Errorf("expected ErrInvalidPolygon for %v, got %v", mp, err)
+			}
+		}
+	})
+
+	t.Run("contains", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			lat, long float64
+			expected  bool
+		}{
+			{"Westminster", 51.4975, -0.1357, true},
+			{"City (in hole)", 51.5155, -0.0922, false},
+			{"Oxford", 51.752, -1.2577, true},
+			{"Reading", 51.4543, -0.9781, false},
+		}
+		for _, tt := range tests {
+			if got := shape.Contains(tt.lat, tt.long); got != tt.expected {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, got)
+			}
+		}
+	})
+
+	t.Run("bounds", func(t *testing.T) {
+		minLat, minLong, maxLat, maxLong := shape.Bounds()
+		if minLat != 51.3 || minLong != -1.35 || maxLat != 51.8 || maxLong != 0.1 {
+			t.Errorf("unexpected bounds: (%f, %f) - (%f, %f)", minLat, minLong, maxLat, maxLong)
+		}
+	})
+
+	t.Run("within shape", func(t *testing.T) {
+		idx := NewSpatialIndex(testPlaces(), 0.1)
+		results := idx.WithinShape(shape)
+		SortByRelevancy(results)
+		if len(results) != 3 {
+			t.Fatalf("expected 3 results, got %d", len(results))
+		}
+		expected := []string{"London", "Oxford", "Croydon"}
+		for i, name := range expected {
+			if results[i].Name != name {
+				t.Errorf("result %d: expected %s, got %s", i, name, results[i].Name)
+			}
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/map-services/placenames-api/internal"
+	"github.com/map-services/placenames-api/pkg/api"
+	pb "github.com/map-services/placenames-api/pkg/pb/placenamesv1"
+	"google.golang.org/protobuf/proto"
+)
+
+const maxDistanceCandidates = 10
+
+// distanceResult renders the distance between two places. The formats made
+// of places list both ends, with the measurements on the second.
+type distanceResult api.DistanceResponse
+
+func (resp distanceResult) body() any {
+	return api.DistanceResponse(resp)
+}
+
+func (resp distanceResult) geoJSON() any {
+	from := placeDetail(resp.From).geoJSON().(Feature)
+	to := placeDetail(resp.To).geoJSON().(Feature)
+	to.Properties["distance_km"] = resp.DistanceKm
+	to.Properties["distance_miles"] = resp.DistanceMiles
+	to.Properties["bearing_degrees"] = resp.BearingDegrees
+	to.Properties["compass"] = resp.Compass
+	return FeatureCollection{Type: "FeatureCollection", Features: []Feature{from, to}}
+}
+
+func (resp distanceResult) records() []Record {
+	to := placeDetail(resp.To).record()
+	to.DistanceKm = &resp.DistanceKm
+	return []Record{placeDetail(resp.From).record(), to}
+}
+
+func (resp distanceResult) protobuf() proto.Message {
+	return &pb.PlaceList{Results: protobufPlaces(resp.records())}
+}
+
+// resolvePlace finds a place by ID, then by exact name. If that doesn't
+// give exactly one place, the candidates are returned instead: either the
+// places sharing the name (ambiguous), or the best prefix matches as
+// suggestions.
+func resolvePlace(trie *internal.Trie, value string) (*internal.Place, []*internal.Place, bool) {
+	if place, ok := trie.FindByID(value); ok {
+		return place, nil, false
+	}
+
+	matches := trie.FindByName(value)
+	if len(matches) == 1 {
+		return matches[0], nil, false
+	}
+
+	ambiguous := len(matches) > 1
+	if !ambiguous {
+		matches = trie.FindByPrefix(value)
+	}
+	return nil, matches[:min(maxDistanceCandidates, len(matches))], ambiguous
+}
+
+func Distance(trie *internal.Trie) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ends [2]*internal.Place
+		unresolved := []string{}
+		candidates := map[string][]api.PlaceDetail{}
+		invalid := []api.InvalidParam{}
+		status, code, reason := http.StatusNotFound, api.CodeNotFound, "could not find a place matching"
+
+		for i, param := range []string{"from", "to"} {
+			value := strings.TrimSpace(c.Query(param))
+			if value == "" {
+				badRequest(c, paramErrorf(param, "%s must be a place ID or name", param))
+				return
+			}
+
+			place, matches, ambiguous := resolvePlace(trie, value)
+			if place != nil {
+				if !place.HasLocation() {
+					detail := fmt.Sprintf("%s place has no location: %s", param, place.ID)
+					abortWithProblem(c, api.Problem{
+						Status:        http.StatusUnprocessableEntity,
+						Code:          api.CodeNoLocation,
+						Detail:        detail,
+						InvalidParams: []api.InvalidParam{{Name: param, Reason: detail}},
+					})
+					return
+				}
+				ends[i] = place
+				continue
+			}
+
+			if ambiguous {
+				status, code, reason = http.StatusMultipleChoices, api.CodeAmbiguousPlace, "more than one place matches"
+			}
+			unresolved = append(unresolved, param)
+			invalid = append(invalid, api.InvalidParam{Name: param, Reason: fmt.Sprintf("%q did not resolve to a single place", value)})
+			candidates[param] = make([]api.PlaceDetail, len(matches))
+			for j, match := range matches {
+				candidates[param][j] = newPlaceDetail(match)
+			}
+		}
+
+		if len(unresolved) > 0 {
+			abortWithProblem(c, api.Problem{
+				Status:        status,
+				Code:          code,
+				Detail:        fmt.Sprintf("%s %s: use one of the candidate IDs instead", reason, strings.Join(unresolved, " and ")),
+				InvalidParams: invalid,
+				Candidates:    candidates,
+			})
+			return
+		}
+
+		from, to := ends[0], ends[1]
+		distance := internal.HaversineKm(from.Lat, from.Long, to.Lat, to.Long)
+		bearing := internal.InitialBearing(from.Lat, from.Long, to.Lat, to.Long)
+		respond(c, distanceResult{
+			From:           newPlaceDetail(from),
+			To:             newPlaceDetail(to),
+			DistanceKm:     distance,
+			DistanceMiles:  internal.KmToMiles(distance),
+			BearingDegrees: bearing,
+			Compass:        internal.CompassPoint(bearing),
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package routes
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	r := newTestRouter(t)
+	const target = "/v1/place-names/distance?from=london&to=oxford"
+
+	t.Run("json", func(t *testing.T) {
+		body := decodeJSON(t, serve(t, r, http.MethodGet, target, nil), http.StatusOK)
+		if distance, _ := body["distance_km"].(float64); distance < 80 || distance > 90 {
+			t.Errorf("expected London to Oxford to be about 84km, got %v", body["distance_km"])
+		}
+		if body["compass"] != "WNW" {
+			t.Errorf("expected Oxford to be WNW of London, got %v", body["compass"])
+		}
+	})
+
+	t.Run("geojson", func(t *testing.T) {
+		w := serve(t, r, http.MethodGet, target, nil, "Accept", MIMEGeoJSON)
+		if contentType := w.Header().Get("Content-Type"); contentType != MIMEGeoJSON {
+			t.Errorf("expected %s, got %s", MIMEGeoJSON, contentType)
+		}
+		features, _ := decodeJSON(t, w, http.StatusOK)["features"].([]any)
+		if len(features) != 2 {
+			t.Fatalf("expected a feature for each end, got %v", features)
+		}
+		to := features[1].(map[string]any)["properties"].(map[string]any)
+		if to["id"] != "oxford" || to["distance_km"] == nil || to["bearing_degrees"] == nil {
+			t.Errorf("expected the measurements on the second feature, got %v", to)
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		w := serve(t, r, http.MethodGet, target+"&format=csv", nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body)
+		}
+		rows, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+		if err != nil || len(rows) != 3 {
+			t.Fatalf("expected a header and two rows, got %v: %s", err, w.Body)
+		}
+		distance := len(csvHeader) - 1
+		if rows[1][0] != "london" || rows[1][distance] != "" || rows[2][0] != "oxford" || rows[2][distance] == "" {
+			t.Errorf("expected the distance on the second row, got %v", rows[1:])
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		decodeJSON(t, serve(t, r, http.MethodGet, target+"&format=xml", nil), http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,462 @@
+package routes
+
+import (
+	"cmp"
+	"context"
+	stdjson "encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/codec/json"
+	"github.com/map-services/placenames-api/internal"
+	"github.com/map-services/placenames-api/pkg/api"
+)
+
+// Elasticsearch compatibility: enough of the _search API for front ends
+// written against the completion suggester, so this service can replace a
+// cluster used only for typeahead. Responses (and errors) follow
+// Elasticsearch's shapes rather than ours.
+// See https://www.elastic.co/guide/en/elasticsearch/reference/current/search-suggesters.html#completion-suggester
+
+const defaultESSuggestSize = 5
+
+type esSearchRequest struct {
+	Query   stdjson.RawMessage            `json:"query"`
+	Suggest map[string]stdjson.RawMessage `json:"suggest"`
+}
+
+type esSuggester struct {
+	Prefix     *string       `json:"prefix"`
+	Text       *string       `json:"text"`
+	Regex      *string       `json:"regex"`
+	Completion *esCompletion `json:"completion"`
+}
+
+type esCompletion struct {
+	Field          string                        `json:"field"`
+	Size           *int                          `json:"size"`
+	SkipDuplicates bool                          `json:"skip_duplicates"`
+	Fuzzy          *esFuzzy                      `json:"fuzzy"`
+	Contexts       map[string]stdjson.RawMessage `json:"contexts"`
+}
+
+// esFuzzy is either true, for the defaults, or an object of options
+type esFuzzy struct {
+	Fuzziness      any  `json:"fuzziness"`
+	Transpositions bool `json:"transpositions"`
+	MinLength      int  `json:"min_length"`
+	PrefixLength   int  `json:"prefix_length"`
+}
+
+func (f *esFuzzy) UnmarshalJSON(data []byte) error {
+	*f = esFuzzy{Fuzziness: "AUTO", Transpositions: true, MinLength: 3, PrefixLength: 1}
+	var enabled bool
+	if err := json.API.Unmarshal(data, &enabled); err == nil {
+		if !enabled {
+			return errors.New("fuzzy must be true or an object")
+		}
+		return nil
+	}
+	type options esFuzzy // without this method
+	return json.API.Unmarshal(data, (*options)(f))
+}
+
+type esSearchResponse struct {
+	Took     int64                          `json:"took"`
+	TimedOut bool                           `json:"timed_out"`
+	Shards   esShards                       `json:"_shards"`
+	Hits     esHits                         `json:"hits"`
+	Suggest  map[string][]esSuggestionEntry `json:"suggest,omitempty"`
+}
+
+type esShards struct {
+	Total      int `json:"total"`
+	Successful int `json:"successful"`
+	Skipped    int `json:"skipped"`
+	Failed     int `json:"failed"`
+}
+
+type esHits struct {
+	Total    esTotal  `json:"total"`
+	MaxScore *float64 `json:"max_score"`
+	Hits     []any    `json:"hits"`
+}
+
+type esTotal struct {
+	Value    int    `json:"value"`
+	Relation string `json:"relation"`
+}
+
+type esSuggestionEntry struct {
+	Text    string     `json:"text"`
+	Offset  int        `json:"offset"`
+	Length  int        `json:"length"`
+	Options []esOption `json:"options"`
+}
+
+type esOption struct {
+	Text   string          `json:"text"`
+	Index  string          `json:"_index"`
+	ID     string          `json:"_id"`
+	Score  float64         `json:"_score"`
+	Source api.PlaceDetail `json:"_source"`
+}
+
+type esErrorResponse struct {
+	Error  esError `json:"error"`
+	Status int     `json:"status"`
+}
+
+type esError struct {
+	RootCause []esError `json:"root_cause,omitempty"`
+	Type      string    `json:"type"`
+	Reason    string    `json:"reason"`
+}
+
+// esRequestError is reported as an Elasticsearch error of the given type
+type esRequestError struct {
+	errType string
+	reason  string
+}
+
+func (e *esRequestError) Error() string {
+	return e.reason
+}
+
+func illegalArgumentf(format string, args ...any) error {
+	return &esRequestError{errType: "illegal_argument_exception", reason: fmt.Sprintf(format, args...)}
+}
+
+func abortWithESError(c *gin.Context, status int, err error) {
+	esErr := esError{Type: "illegal_argument_exception", Reason: err.Error()}
+	var reqErr *esRequestError
+	if errors.As(err, &reqErr) {
+		esErr.Type = reqErr.errType
+	}
+	esErr.RootCause = []esError{esErr}
+	c.AbortWithStatusJSON(status, esErrorResponse{Error: esErr, Status: status})
+}
+
+// ElasticProduct marks responses as coming from Elasticsearch, which the
+// official clients check for.
+func ElasticProduct(c *gin.Context) {
+	c.Header("X-Elastic-Product", "Elasticsearch")
+	c.Next()
+}
+
+// ElasticsearchSearch answers completion suggesters in a _search body. The
+// index is only echoed back, as there's only the one.
+func ElasticsearchSearch(trie *internal.Trie) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		var req esSearchRequest
+		limitBody(c)
+		err := c.ShouldBindJSON(&req)
+		if tooLarge := (*http.MaxBytesError)(nil); errors.As(err, &tooLarge) {
+			abortWithESError(c, http.StatusRequestEntityTooLarge, &esRequestError{
+				errType: "content_too_long_exception",
+				reason:  fmt.Sprintf("the request body must be no more than %d bytes", tooLarge.Limit),
+			})
+			return
+		}
+		if err != nil {
+			abortWithESError(c, http.StatusBadRequest, &esRequestError{errType: "parsing_exception", reason: err.Error()})
+			return
+		}
+		if req.Query != nil {
+			abortWithESError(c, http.StatusBadRequest, illegalArgumentf("only completion suggesters are supported, not queries"))
+			return
+		}
+
+		resp := esSearchResponse{
+			Shards: esShards{Total: 1, Successful: 1},
+			Hits:   esHits{Total: esTotal{Relation: "eq"}, Hits: []any{}},
+		}
+		if len(req.Suggest) > 0 {
+			resp.Suggest, err = esSuggest(c.Request.Context(), trie, c.Param("index"), req.Suggest)
+			if errors.Is(err, context.Canceled) {
+				c.Abort() // the client has gone
+				return
+			}
+			if err != nil {
+				abortWithESError(c, http.StatusBadRequest, err)
+				return
+			}
+		}
+		resp.Took = time.Since(start).Milliseconds()
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func esSuggest(ctx context.Context, trie *internal.Trie, index string, suggest map[string]stdjson.RawMessage) (map[string][]esSuggestionEntry, error) {
+	// text at the top level is shared by every suggester without its own
+	var globalText *string
+	if raw, ok := suggest["text"]; ok {
+		if err := json.API.Unmarshal(raw, &globalText); err != nil {
+			return nil, &esRequestError{errType: "parsing_exception", reason: "suggest text must be a string"}
+		}
+	}
+
+	results := make(map[string][]esSuggestionEntry, len(suggest))
+	for name, raw := range suggest {
+		if name == "text" {
+			continue
+		}
+		var suggester esSuggester
+		if err := json.API.Unmarshal(raw, &suggester); err != nil {
+			return nil, &esRequestError{errType: "parsing_exception", reason: fmt.Sprintf("[%s] %v", name, err)}
+		}
+		entry, err := esComplete(ctx, trie, index, suggester, globalText)
+		if err != nil {
+			return nil, fmt.Errorf("[%s] %w", name, err)
+		}
+		results[name] = []esSuggestionEntry{entry}
+	}
+	return results, nil
+}
+
+func esComplete(ctx context.Context, trie *internal.Trie, index string, suggester esSuggester, globalText *string) (esSuggestionEntry, error) {
+	var entry esSuggestionEntry
+	completion := suggester.Completion
+	switch {
+	case completion == nil:
+		return entry, illegalArgumentf("only completion suggesters are supported")
+	case suggester.Regex != nil:
+		return entry, illegalArgumentf("regex completion is not supported")
+	}
+	text := suggester.Prefix
+	if text == nil {
+		text = cmp.Or(suggester.Text, globalText)
+	}
+	if text == nil {
+		return entry, illegalArgumentf("a prefix or text is required")
+	}
+	if utf8.RuneCountInString(*text) > maxQueryLength {
+		return entry, illegalArgumentf("the prefix must be no more than %d characters", maxQueryLength)
+	}
+	size := defaultESSuggestSize
+	if completion.Size != nil {
+		size = *completion.Size
+	}
+	if size < 1 || size > trie.TopK() {
+		return entry, illegalArgumentf("size must be between 1 and %d", trie.TopK())
+	}
+	accept, err := esContexts(completion.Contexts)
+	if err != nil {
+		return entry, err
+	}
+
+	entry = esSuggestionEntry{Text: *text, Length: utf8.RuneCountInString(*text), Options: []esOption{}}
+	if strings.TrimSpace(*text) == "" {
+		return entry, nil
+	}
+
+	// fetch extra when skipping duplicates, as several places share a name
+	limit := size
+	if completion.SkipDuplicates {
+		limit = trie.TopK()
+	}
+	var places []*internal.Place
+	if fuzzy := completion.Fuzzy; fuzzy != nil {
+		opts := internal.FuzzyOptions{PrefixLength: fuzzy.PrefixLength, Transpositions: fuzzy.Transpositions}
+		if opts.MaxEdits, err = esFuzziness(fuzzy.Fuzziness, entry.Length); err != nil {
+			return entry, err
+		}
+		if entry.Length < fuzzy.MinLength {
+			opts.MaxEdits = 0
+		}
+		for _, match := range trie.FindFuzzy(*text, opts, limit, accept) {
+			places = append(places, match.Place)
+		}
+	} else {
+		if places, err = trie.FindByPrefixContext(ctx, *text, limit, accept); err != nil {
+			return entry, err
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, place := range places {
+		name := strings.ToLower(place.Name)
+		if completion.SkipDuplicates && seen[name] {
+			continue
+		}
+		seen[name] = true
+		entry.Options = append(entry.Options, esOption{
+			Text:   place.Name,
+			Index:  index,
+			ID:     place.ID,
+			Score:  place.Relevancy,
+			Source: newPlaceDetail(place),
+		})
+		if len(entry.Options) == size {
+			break
+		}
+	}
+	return entry, nil
+}
+
+// esFuzziness is the maximum edits for the fuzziness option: 0, 1 or 2, or
+// AUTO, which allows more edits for longer prefixes.
+func esFuzziness(fuzziness any, length int) (int, error) {
+	switch value := fuzziness.(type) {
+	case float64:
+		if value == 0 || value == 1 || value == 2 {
+			return int(value), nil
+		}
+	case string:
+		if edits, err := strconv.Atoi(value); err == nil && edits >= 0 && edits <= 2 {
+			return edits, nil
+		}
+		low, high := 3, 6
+		if value == "AUTO" || esAutoFuzziness(value, &low, &high) {
+			switch {
+			case length < low:
+				return 0, nil
+			case length < high:
+				return 1, nil
+			default:
+				return 2, nil
+			}
+		}
+	}
+	return 0, illegalArgumentf("fuzziness must be 0, 1, 2 or AUTO, got %v", fuzziness)
+}
+
+// esAutoFuzziness parses AUTO:low,high
+func esAutoFuzziness(value string, low, high *int) bool {
+	bounds, ok := strings.CutPrefix(value, "AUTO:")
+	if !ok {
+		return false
+	}
+	lowStr, highStr, ok := strings.Cut(bounds, ",")
+	if !ok {
+		return false
+	}
+	var err1, err2 error
+	*low, err1 = strconv.Atoi(lowStr)
+	*high, err2 = strconv.Atoi(highStr)
+	return err1 == nil && err2 == nil && *low <= *high
+}
+
+// esGeoContexts are the names understood as geo contexts
+var esGeoContexts = []string{"location", "geo", "geohash"}
+
+const defaultESGeoPrecision = 6
+
+// esContexts turns the contexts into a predicate: a place must match one of
+// the values given for each context. The category contexts are the place
+// attributes. Boosts are accepted but ignored.
+func esContexts(contexts map[string]stdjson.RawMessage) (func(*internal.Place) bool, error) {
+	var predicates []func(*internal.Place) bool
+	for name, raw := range contexts {
+		values, err := esContextValues(raw)
+		if err != nil {
+			return nil, fmt.Errorf("context [%s]: %w", name, err)
+		}
+
+		if _, ok := placeAttributes[name]; ok {
+			var categories []string
+			for _, value := range values {
+				category, ok := value.context.(string)
+				if !ok {
+					return nil, illegalArgumentf("context [%s] must be a string", name)
+				}
+				categories = append(categories, category)
+			}
+			predicates = append(predicates, attributeIn(name, categories))
+		} else if slices.Contains(esGeoContexts, name) {
+			var cells []string
+			for _, value := range values {
+				cell, err := value.geohash()
+				if err != nil {
+					return nil, fmt.Errorf("context [%s]: %w", name, err)
+				}
+				cells = append(cells, cell)
+			}
+			predicates = append(predicates, func(place *internal.Place) bool {
+				return slices.ContainsFunc(cells, func(cell string) bool {
+					return strings.HasPrefix(place.Geohash, cell)
+				})
+			})
+		} else {
+			return nil, illegalArgumentf("unknown context [%s]", name)
+		}
+	}
+	return allOf(predicates), nil
+}
+
+type esContextValue struct {
+	context   any // a category string, or a geo point
+	precision any
+}
+
+// esContextValues accepts a single value or a list of them, each either
+// bare or as an object with the value under "context" (plus options).
+func esContextValues(raw stdjson.RawMessage) ([]esContextValue, error) {
+	var decoded any
+	if err := json.API.Unmarshal(raw, &decoded); err != nil {
+		return nil, err
+	}
+	list, ok := decoded.([]any)
+	if !ok {
+		list = []any{decoded}
+	}
+
+	values := make([]esContextValue, len(list))
+	for i, item := range list {
+		object, ok := item.(map[string]any)
+		if !ok {
+			values[i] = esContextValue{context: item}
+			continue
+		}
+		if context, ok := object["context"]; ok {
+			values[i] = esContextValue{context: context, precision: object["precision"]}
+		} else {
+			values[i] = esContextValue{context: object} // a bare geo point
+		}
+	}
+	return values, nil
+}
+
+// geohash is the cell the geo context's point falls in, at its precision
+// (a geohash length from 1 to 12). Places are only located to
+// GeohashPrecision, so any finer cell is widened to that.
+func (value esContextValue) geohash() (string, error) {
+	point, ok := value.context.(map[string]any)
+	if !ok {
+		if geohash, ok := value.context.(string); ok {
+			geohash = strings.TrimSpace(geohash)
+			if len(geohash) <= internal.MaxGeohashPrecision {
+				geohash = geohash[:min(len(geohash), internal.GeohashPrecision)]
+			}
+			return internal.ParseGeohash(geohash)
+		}
+		return "", illegalArgumentf("a geo context must be a point or geohash")
+	}
+	lat, latOK := point["lat"].(float64)
+	long, longOK := point["lon"].(float64)
+	if !latOK || !longOK || lat < -90 || lat > 90 || long < -180 || long > 180 {
+		return "", illegalArgumentf("a geo context point needs a valid lat and lon")
+	}
+
+	precision := defaultESGeoPrecision
+	switch p := value.precision.(type) {
+	case nil:
+	case float64:
+		precision = int(p)
+	default:
+		return "", illegalArgumentf("precision must be a geohash length, distances are not supported")
+	}
+	if precision < 1 || precision > internal.MaxGeohashPrecision {
+		return "", illegalArgumentf("precision must be between 1 and %d", internal.MaxGeohashPrecision)
+	}
+	return internal.EncodeGeohash(lat, long, min(precision, internal.GeohashPrecision)), nil
+}
@@ -0,0 +1,96 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestElasticsearchSearch(t *testing.T) {
+	r := newTestRouter(t)
+
+	t.Run("suggest", func(t *testing.T) {
+		w := serve(t, r, http.MethodPost, "/es/places/_search",
+			strings.NewReader(`{"suggest": {"place": {"prefix": "new", "completion": {"field": "suggest", "size": 2}}}}`),
+			"Content-Type", "application/json")
+		if product := w.Header().Get("X-Elastic-Product"); product != "Elasticsearch" {
+			t.Errorf("expected X-Elastic-Product: Elasticsearch, got %q", product)
+		}
+		body := decodeJSON(t, w, http.StatusOK)
+		checkShape(t, "response", body, `{
+			"took": 0,
+			"timed_out": false,
+			"_shards": {"total": 1, "successful": 1, "skipped": 0, "failed": 0},
+			"hits": {"total": {"value": 0, "relation": "eq"}, "max_score": null, "hits": []},
+			"suggest": {"place": [{
+				"text": "new", "offset": 0, "length": 3,
+				"options": [{
+					"text": "Newport", "_index": "places", "_id": "newport-wales", "_score": 0.8,
+					"_source": {"id": "newport-wales", "name": "Newport", "relevancy": 0.8, "type": "city", "country": "Wales", "lat": 51.5842, "long": -2.9977}
+				}]
+			}]}
+		}`)
+
+		entry := body["suggest"].(map[string]any)["place"].([]any)[0].(map[string]any)
+		if entry["text"] != "new" || entry["length"] != float64(3) {
+			t.Errorf("expected the entry to echo the prefix, got %v", entry)
+		}
+		options := entry["options"].([]any)
+		if len(options) != 2 {
+			t.Fatalf("expected 2 options, got %v", options)
+		}
+		for i, id := range []string{"newport-wales", "newport-iow"} {
+			if option := options[i].(map[string]any); option["_id"] != id || option["_index"] != "places" {
+				t.Errorf("expected option %d to be %s in places, got %v", i, id, option)
+			}
+		}
+	})
+
+	t.Run("client gone", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/es/places/_search",
+			strings.NewReader(`{"suggest": {"place": {"prefix": "n", "completion": {"field": "suggest", "contexts": {"country": "Wales"}}}}}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Body.Len() != 0 {
+			t.Errorf("expected the search to be abandoned, got %s", w.Body)
+		}
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		body := `{"suggest": {"place": {"prefix": "` + strings.Repeat("n", maxBodyBytes) + `"}}}`
+		w := serve(t, r, http.MethodPost, "/es/places/_search", strings.NewReader(body), "Content-Type", "application/json")
+		resp := decodeJSON(t, w, http.StatusRequestEntityTooLarge)
+		if esErr, _ := resp["error"].(map[string]any); esErr["type"] != "content_too_long_exception" || resp["status"] != float64(413) {
+			t.Errorf("expected a content_too_long_exception, got %v", resp)
+		}
+	})
+
+	for name, body := range map[string]string{
+		"query":   `{"query": {"match_all": {}}}`,
+		"invalid": `{"suggest":`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := serve(t, r, http.MethodPost, "/es/places/_search", strings.NewReader(body), "Content-Type", "application/json")
+			resp := decodeJSON(t, w, http.StatusBadRequest)
+			checkShape(t, "response", resp, `{
+				"error": {
+					"root_cause": [{"type": "illegal_argument_exception", "reason": "..."}],
+					"type": "illegal_argument_exception",
+					"reason": "..."
+				},
+				"status": 400
+			}`)
+
+			esErr := resp["error"].(map[string]any)
+			rootCause := esErr["root_cause"].([]any)[0].(map[string]any)
+			if rootCause["type"] != esErr["type"] || rootCause["reason"] != esErr["reason"] || resp["status"] != float64(400) {
+				t.Errorf("expected the root cause to be the error, got %v", resp)
+			}
+		})
+	}
+}
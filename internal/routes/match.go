@@ -0,0 +1,377 @@
+package routes
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/codec/json"
+	"github.com/map-services/placenames-api/internal"
+	"github.com/map-services/placenames-api/pkg/api"
+)
+
+const (
+	defaultMaxAlternatives = 3
+	maxAlternatives        = 10
+
+	// rows between flushes, so results arrive while the input is still
+	// being sent
+	matchFlushInterval = 100
+
+	maxNDJSONLine = 64 * 1024
+)
+
+// MatchRow is the best match for a row of the input, with the runners up
+type MatchRow struct {
+	Row          int      `json:"row"` // counting from 1, after any header
+	Input        string   `json:"input"`
+	MatchType    string   `json:"match_type"`
+	Confidence   float64  `json:"confidence"`
+	Match        *Record  `json:"match,omitempty"`
+	Alternatives []Record `json:"alternatives"`
+	Error        string   `json:"error,omitempty"` // why the row couldn't be matched
+}
+
+// matchInput is a row of the input: the name, and any place attributes
+// narrowing down its matches
+type matchInput struct {
+	row        int
+	name       string
+	attributes map[string]string
+	err        error
+}
+
+// Match finds the best match for each row of a CSV or NDJSON body of place
+// names, streaming the results back as each row is read, so inputs of any
+// size can be matched without being held in memory.
+func Match(trie *internal.Trie, matcher *internal.Matcher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		alternatives, err := parseMaxAlternatives(c)
+		if err != nil {
+			badRequest(c, err)
+			return
+		}
+		inputFormat := c.ContentType()
+		if inputFormat != MIMECSV && inputFormat != MIMENDJSON {
+			abortWithStatus(c, http.StatusUnsupportedMediaType, api.CodeUnsupportedMediaType,
+				fmt.Sprintf("the body must be %s or %s", MIMECSV, MIMENDJSON))
+			return
+		}
+		format, err := negotiateMatchFormat(c, inputFormat)
+		if err != nil {
+			badRequest(c, err)
+			return
+		}
+
+		body := io.Reader(c.Request.Body)
+		if c.GetHeader("Content-Encoding") == "gzip" {
+			gzReader, err := gzip.NewReader(body)
+			if err != nil {
+				abortWithStatus(c, http.StatusBadRequest, api.CodeInvalidBody, "the body is not valid gzip")
+				return
+			}
+			defer func() { _ = gzReader.Close() }()
+			body = gzReader
+		}
+
+		var rows iter.Seq[matchInput]
+		if inputFormat == MIMECSV {
+			if rows, err = readMatchCSV(body, c.Query("column")); err != nil {
+				badRequest(c, err)
+				return
+			}
+		} else {
+			rows = readMatchNDJSON(body, c.Query("column"))
+		}
+
+		// HTTP/1 stops reading the body once the response starts, unless
+		// told otherwise
+		_ = http.NewResponseController(c.Writer).EnableFullDuplex()
+		writer := newMatchRowWriter(c.Writer, format)
+		c.Status(http.StatusOK)
+		flush := func() error {
+			err := writer.flush()
+			c.Writer.Flush()
+			return err
+		}
+
+		limit := max(trie.TopK(), alternatives+1)
+		for input := range rows {
+			if c.Request.Context().Err() != nil {
+				return // the client has gone
+			}
+			if err := writer.write(matchRow(matcher, input, limit, alternatives)); err != nil {
+				_ = c.Error(err)
+				return
+			}
+			if input.row%matchFlushInterval == 0 {
+				if err := flush(); err != nil {
+					_ = c.Error(err)
+					return
+				}
+			}
+		}
+		if err := flush(); err != nil {
+			_ = c.Error(err)
+		}
+	}
+}
+
+func parseMaxAlternatives(c *gin.Context) (int, error) {
+	value := c.Query("max_alternatives")
+	if value == "" {
+		return defaultMaxAlternatives, nil
+	}
+	if n, err := strconv.Atoi(value); err == nil && n >= 0 && n <= maxAlternatives {
+		return n, nil
+	}
+	return 0, paramErrorf("max_alternatives", "max_alternatives must be an integer between 0 and %d", maxAlternatives)
+}
+
+// negotiateMatchFormat picks CSV or NDJSON from ?format=, falling back to
+// the Accept header, and then to the format of the input.
+func negotiateMatchFormat(c *gin.Context, inputFormat string) (string, error) {
+	switch c.Query("format") {
+	case "":
+	case FormatCSV:
+		return MIMECSV, nil
+	case FormatNDJSON:
+		return MIMENDJSON, nil
+	default:
+		return "", paramErrorf("format", "format must be one of: %s, %s", FormatCSV, FormatNDJSON)
+	}
+	offered := []string{inputFormat, MIMECSV, MIMENDJSON}
+	return cmp.Or(c.NegotiateFormat(offered...), inputFormat), nil
+}
+
+// matchRow matches the input's name, the best of up to limit candidates
+// going into the row and the next best into its alternatives.
+func matchRow(matcher *internal.Matcher, input matchInput, limit, alternatives int) MatchRow {
+	row := MatchRow{Row: input.row, Input: input.name, MatchType: internal.MatchNone, Alternatives: []Record{}}
+	if input.err != nil {
+		row.Error = input.err.Error()
+		return row
+	}
+	if utf8.RuneCountInString(input.name) > maxQueryLength {
+		row.Error = fmt.Sprintf("the name must be no more than %d characters", maxQueryLength)
+		return row
+	}
+
+	var predicates []func(*internal.Place) bool
+	for attribute, value := range input.attributes {
+		if value != "" {
+			predicates = append(predicates, attributeIn(attribute, []string{value}))
+		}
+	}
+	matches := matcher.Match(input.name, limit, allOf(predicates))
+	if len(matches) == 0 {
+		return row
+	}
+
+	best := matches[0]
+	row.MatchType = best.Type
+	row.Confidence = matchConfidence(matches)
+	record := newRecord(best.Place, best.Place.Name)
+	row.Match = &record
+	for _, match := range matches[1:min(alternatives+1, len(matches))] {
+		row.Alternatives = append(row.Alternatives, newRecord(match.Place, match.Place.Name))
+	}
+	return row
+}
+
+// matchConfidence is the best match's score, shared with any other
+// candidates matched the same way: two equally good Newports are each no
+// better than a coin toss.
+func matchConfidence(matches []internal.Match) float64 {
+	best := matches[0]
+	total := 0.0
+	for _, match := range matches {
+		if match.Type == best.Type {
+			total += match.Score
+		}
+	}
+	return math.Round(best.Score*best.Score/total*1000) / 1000
+}
+
+// readMatchCSV reads the header of a CSV body, then yields its rows. The
+// names are in the given column (by default "name", or failing that the
+// first), and any columns named after place attributes narrow down the
+// matches.
+func readMatchCSV(body io.Reader, column string) (iter.Seq[matchInput], error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return func(func(matchInput) bool) {}, nil
+	}
+	if err != nil {
+		return nil, &api.Problem{Status: http.StatusBadRequest, Code: api.CodeInvalidBody, Detail: fmt.Sprintf("failed to read the CSV header: %v", err)}
+	}
+
+	nameColumn := -1
+	attributeColumns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))) // as saved by Excel
+		if name == cmp.Or(strings.ToLower(column), "name") {
+			nameColumn = i
+		} else if _, ok := placeAttributes[name]; ok {
+			attributeColumns[name] = i
+		}
+	}
+	if nameColumn < 0 {
+		if column != "" {
+			return nil, paramErrorf("column", "the CSV header has no %s column", column)
+		}
+		nameColumn = 0
+	}
+
+	return func(yield func(matchInput) bool) {
+		for row := 1; ; row++ {
+			rec, err := reader.Read()
+			if err == io.EOF {
+				return
+			}
+			input := matchInput{row: row, attributes: map[string]string{}}
+			var parseErr *csv.ParseError
+			switch {
+			case errors.As(err, &parseErr):
+				input.err = parseErr
+			case err != nil:
+				input.err = err
+				yield(input)
+				return // the body can't be read any further
+			case nameColumn >= len(rec):
+				input.err = fmt.Errorf("the row has no column %d", nameColumn+1)
+			default:
+				input.name = strings.TrimSpace(rec[nameColumn])
+				for attribute, i := range attributeColumns {
+					if i < len(rec) {
+						input.attributes[attribute] = strings.TrimSpace(rec[i])
+					}
+				}
+			}
+			if !yield(input) {
+				return
+			}
+		}
+	}, nil
+}
+
+// readMatchNDJSON yields the lines of an NDJSON body, skipping blank ones.
+// Each is a name, or an object with the name under the given key (by
+// default "name") and optionally place attributes narrowing down the
+// matches.
+func readMatchNDJSON(body io.Reader, key string) iter.Seq[matchInput] {
+	key = cmp.Or(key, "name")
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 4096), maxNDJSONLine)
+
+	return func(yield func(matchInput) bool) {
+		row := 0
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			row++
+			input := matchInput{row: row, attributes: map[string]string{}}
+			var value any
+			if err := json.API.Unmarshal(line, &value); err != nil {
+				input.err = fmt.Errorf("invalid JSON: %w", err)
+			} else {
+				switch v := value.(type) {
+				case string:
+					input.name = strings.TrimSpace(v)
+				case map[string]any:
+					name, ok := v[key].(string)
+					if !ok {
+						input.err = fmt.Errorf("%s must be a string", key)
+						break
+					}
+					input.name = strings.TrimSpace(name)
+					for attribute := range placeAttributes {
+						if value, ok := v[attribute].(string); ok {
+							input.attributes[attribute] = strings.TrimSpace(value)
+						}
+					}
+				default:
+					input.err = fmt.Errorf("each line must be a name or an object")
+				}
+			}
+			if !yield(input) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			yield(matchInput{row: row + 1, err: err}) // the body can't be read any further
+		}
+	}
+}
+
+var matchCSVHeader = append(append([]string{"row", "input", "match_type", "confidence"}, csvHeader...), "alternatives", "error")
+
+// matchRowWriter writes the rows in a format, buffering them until flushed
+type matchRowWriter interface {
+	write(row MatchRow) error
+	flush() error
+}
+
+func newMatchRowWriter(w gin.ResponseWriter, format string) matchRowWriter {
+	if format == MIMENDJSON {
+		w.Header().Set("Content-Type", MIMENDJSON)
+		return ndjsonMatchWriter{encoder: json.API.NewEncoder(w)}
+	}
+	w.Header().Set("Content-Type", MIMECSV+"; charset=utf-8")
+	writer := csv.NewWriter(w)
+	_ = writer.Write(matchCSVHeader)
+	return csvMatchWriter{writer: writer}
+}
+
+type ndjsonMatchWriter struct {
+	encoder json.Encoder
+}
+
+func (w ndjsonMatchWriter) write(row MatchRow) error {
+	return w.encoder.Encode(row)
+}
+
+func (w ndjsonMatchWriter) flush() error {
+	return nil
+}
+
+// csvMatchWriter flattens each row into the match's Record columns, and the
+// alternatives' IDs separated by semicolons.
+type csvMatchWriter struct {
+	writer *csv.Writer
+}
+
+func (w csvMatchWriter) write(row MatchRow) error {
+	record := make([]string, len(csvHeader))
+	if row.Match != nil {
+		record = row.Match.csvRow()
+	}
+	ids := make([]string, len(row.Alternatives))
+	for i, alternative := range row.Alternatives {
+		ids[i] = alternative.ID
+	}
+	fields := append([]string{strconv.Itoa(row.Row), row.Input, row.MatchType, strconv.FormatFloat(row.Confidence, 'f', -1, 64)}, record...)
+	return w.writer.Write(append(fields, strings.Join(ids, ";"), row.Error))
+}
+
+func (w csvMatchWriter) flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
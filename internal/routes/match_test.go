@@ -0,0 +1,167 @@
+package routes
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// matchRows decodes an NDJSON match response
+func matchRows(t *testing.T, w *httptest.ResponseRecorder) []MatchRow {
+	t.Helper()
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body)
+	}
+	var rows []MatchRow
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var row MatchRow
+		if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+			t.Fatalf("expected a row of JSON, got %v: %s", err, scanner.Bytes())
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// matchIDs are the IDs of each row's match, or "" where there wasn't one
+func matchIDs(rows []MatchRow) []string {
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		if row.Match != nil {
+			ids[i] = row.Match.ID
+		}
+	}
+	return ids
+}
+
+// flushRecorder notes how much of the body had been written at each flush
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushes []int
+}
+
+func (w *flushRecorder) Flush() {
+	w.flushes = append(w.flushes, w.Body.Len())
+	w.ResponseRecorder.Flush()
+}
+
+func TestMatch(t *testing.T) {
+	r := newTestRouter(t)
+
+	t.Run("csv with a BOM and a column", func(t *testing.T) {
+		body := "\ufeffPlace,Country,Ref\nNewport,Wales,1\nNewport,England,2\nOxford,,3\n"
+		w := serve(t, r, http.MethodPost, "/v1/place-names/match?column=place&format=ndjson", strings.NewReader(body),
+			"Content-Type", MIMECSV)
+		rows := matchRows(t, w)
+		if ids := matchIDs(rows); fmt.Sprint(ids) != "[newport-wales newport-iow oxford]" {
+			t.Errorf("expected the place column's names narrowed down by country, got %v", ids)
+		}
+		if rows[0].Row != 1 || rows[0].Input != "Newport" || rows[0].MatchType != "exact" {
+			t.Errorf("expected the first row to be counted after the header, got %+v", rows[0])
+		}
+	})
+
+	t.Run("csv column not in the header", func(t *testing.T) {
+		w := serve(t, r, http.MethodPost, "/v1/place-names/match?column=town", strings.NewReader("name\nOxford\n"),
+			"Content-Type", MIMECSV)
+		decodeJSON(t, w, http.StatusBadRequest)
+	})
+
+	t.Run("malformed csv row", func(t *testing.T) {
+		body := "id,name\n1,Oxford\n2\n3,Luton\n"
+		w := serve(t, r, http.MethodPost, "/v1/place-names/match?format=ndjson", strings.NewReader(body),
+			"Content-Type", MIMECSV)
+		rows := matchRows(t, w)
+		if len(rows) != 3 {
+			t.Fatalf("expected a row for each input row, got %+v", rows)
+		}
+		if rows[1].Error == "" || rows[1].Match != nil || rows[1].MatchType != "none" {
+			t.Errorf("expected the short row to have an error, got %+v", rows[1])
+		}
+		if ids := matchIDs(rows); ids[0] != "oxford" || ids[2] != "luton" {
+			t.Errorf("expected matching to carry on past the error, got %v", ids)
+		}
+	})
+
+	t.Run("ndjson strings and objects", func(t *testing.T) {
+		body := `"Oxford"` + "\n\n" + `{"name": "Newport", "country": "England"}` + "\n" + `{"place": "Luton"}` + "\n" + `[1]` + "\n"
+		w := serve(t, r, http.MethodPost, "/v1/place-names/match", strings.NewReader(body), "Content-Type", MIMENDJSON)
+		if contentType := w.Header().Get("Content-Type"); contentType != MIMENDJSON {
+			t.Errorf("expected the response to default to the body's format, got %s", contentType)
+		}
+		rows := matchRows(t, w)
+		if len(rows) != 4 {
+			t.Fatalf("expected a row per line, skipping blank ones, got %+v", rows)
+		}
+		if ids := matchIDs(rows); ids[0] != "oxford" || ids[1] != "newport-iow" {
+			t.Errorf("expected a name and an object with attributes to match, got %v", ids)
+		}
+		if rows[2].Error != "name must be a string" || rows[3].Error != "each line must be a name or an object" {
+			t.Errorf("expected errors for the object without a name and the array, got %+v", rows[2:])
+		}
+	})
+
+	t.Run("gzip", func(t *testing.T) {
+		var body bytes.Buffer
+		gz := gzip.NewWriter(&body)
+		_, _ = gz.Write([]byte("name\nOxford\nSt Albans\n"))
+		_ = gz.Close()
+		w := serve(t, r, http.MethodPost, "/v1/place-names/match?format=ndjson", &body,
+			"Content-Type", MIMECSV, "Content-Encoding", "gzip")
+		if ids := matchIDs(matchRows(t, w)); fmt.Sprint(ids) != "[oxford st-albans]" {
+			t.Errorf("expected the gzipped names to match, got %v", ids)
+		}
+
+		w = serve(t, r, http.MethodPost, "/v1/place-names/match", strings.NewReader("name\nOxford\n"),
+			"Content-Type", MIMECSV, "Content-Encoding", "gzip")
+		decodeJSON(t, w, http.StatusBadRequest)
+	})
+
+	t.Run("unsupported media type", func(t *testing.T) {
+		w := serve(t, r, http.MethodPost, "/v1/place-names/match", strings.NewReader(`{"names": ["Oxford"]}`),
+			"Content-Type", "application/json")
+		if problem := decodeJSON(t, w, http.StatusUnsupportedMediaType); problem["code"] != "unsupported_media_type" {
+			t.Errorf("expected an unsupported_media_type problem, got %v", problem)
+		}
+	})
+
+	t.Run("csv output", func(t *testing.T) {
+		w := serve(t, r, http.MethodPost, "/v1/place-names/match?max_alternatives=1", strings.NewReader(`"Newport"`+"\n"),
+			"Content-Type", MIMENDJSON, "Accept", MIMECSV)
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		if len(lines) != 2 || lines[0] != strings.Join(matchCSVHeader, ",") {
+			t.Fatalf("expected the header and a row, got %s", w.Body)
+		}
+		if !strings.HasPrefix(lines[1], "1,Newport,exact,") || !strings.HasSuffix(lines[1], ",newport-iow,") {
+			t.Errorf("expected the match and its alternative's ID, got %s", lines[1])
+		}
+	})
+
+	t.Run("flushes as it goes", func(t *testing.T) {
+		var body strings.Builder
+		for range 2*matchFlushInterval + 50 {
+			body.WriteString(`"Oxford"` + "\n")
+		}
+		req := httptest.NewRequest(http.MethodPost, "/v1/place-names/match", strings.NewReader(body.String()))
+		req.Header.Set("Content-Type", MIMENDJSON)
+		w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+		r.ServeHTTP(w, req)
+
+		length := w.Body.Len()
+		rows := matchRows(t, w.ResponseRecorder)
+		if len(rows) != 2*matchFlushInterval+50 {
+			t.Fatalf("expected %d rows, got %d", 2*matchFlushInterval+50, len(rows))
+		}
+		if len(w.flushes) != 3 || w.flushes[0] == 0 || w.flushes[0] >= w.flushes[1] || w.flushes[2] != length {
+			t.Errorf("expected a flush every %d rows and at the end, got flushes at %v of %d bytes",
+				matchFlushInterval, w.flushes, length)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/map-services/placenames-api/internal"
+	"github.com/map-services/placenames-api/pkg/api"
+)
+
+const (
+	defaultRadiusKm = 10.0
+	maxRadiusKm     = 100.0
+)
+
+// Orderings for nearby results, selected with ?order=
+const (
+	OrderDistance  = "distance"
+	OrderRelevancy = "relevancy"
+)
+
+func parseRadius(c *gin.Context) (float64, error) {
+	radiusStr := c.Query("radius_km")
+	if radiusStr == "" {
+		return defaultRadiusKm, nil
+	}
+	if radius, err := strconv.ParseFloat(radiusStr, 64); err == nil && radius > 0 && radius <= maxRadiusKm {
+		return radius, nil
+	}
+	return 0, paramErrorf("radius_km", "radius_km must be a positive number less than or equal to %g", maxRadiusKm)
+}
+
+func Nearby(trie *internal.Trie, spatial *internal.SpatialIndex) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		origin, ok := trie.FindByID(id)
+		if !ok {
+			abortWithStatus(c, http.StatusNotFound, api.CodeNotFound, fmt.Sprintf("no place found with id: %s", id))
+			return
+		}
+		if !origin.HasLocation() {
+			abortWithStatus(c, http.StatusUnprocessableEntity, api.CodeNoLocation, fmt.Sprintf("place has no location: %s", id))
+			return
+		}
+
+		maxResults, err := parseMaxResults(c, trie.TopK())
+		if err != nil {
+			badRequest(c, err)
+			return
+		}
+		radius, err := parseRadius(c)
+		if err != nil {
+			badRequest(c, err)
+			return
+		}
+		fields, err := parseFields(c.Query("fields"))
+		if err != nil {
+			badRequest(c, err)
+			return
+		}
+		order := c.DefaultQuery("order", OrderDistance)
+		if order != OrderDistance && order != OrderRelevancy {
+			badRequest(c, paramErrorf("order", "order must be one of: %s, %s", OrderDistance, OrderRelevancy))
+			return
+		}
+
+		filter, err := parseFilter(c)
+		if err != nil {
+			badRequest(c, err)
+			return
+		}
+		accept := func(place *internal.Place) bool {
+			return place != origin && filter.Matches(place)
+		}
+
+		// ordering by relevancy needs every candidate within the radius
+		k := maxResults
+		if order == OrderRelevancy {
+			k = spatial.Len()
+		}
+		neighbours := spatial.Nearest(origin.Lat, origin.Long, k, radius, accept)
+		if order == OrderRelevancy {
+			sort.SliceStable(neighbours, func(i, j int) bool {
+				return neighbours[i].Place.Relevancy > neighbours[j].Place.Relevancy
+			})
+			neighbours = neighbours[:min(maxResults, len(neighbours))]
+		}
+
+		respond(c, placeList{api.PlaceResponse{MatchType: api.MatchTypeNearby, Results: neighbourResults(neighbours, fields)}, trie})
+	}
+}
@@ -0,0 +1,63 @@
+package routes
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestNearby(t *testing.T) {
+	r := newTestRouter(t)
+
+	for name, test := range map[string]struct {
+		query string
+		want  []string
+	}{
+		"default radius": {"", []string{}},
+		"radius":         {"?radius_km=20", []string{"croydon"}},
+		"by distance":    {"?radius_km=100&order=distance", []string{"croydon", "st-albans", "oxford"}},
+		"by relevancy":   {"?radius_km=100&order=relevancy", []string{"oxford", "croydon", "st-albans"}},
+		"max results":    {"?radius_km=100&order=relevancy&max_results=1", []string{"oxford"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			body := decodeJSON(t, serve(t, r, http.MethodGet, "/v1/place-names/london/nearby"+test.query, nil), http.StatusOK)
+			if body["match_type"] != "nearby" {
+				t.Errorf("expected a nearby match, got %v", body["match_type"])
+			}
+			if ids := resultIDs(t, body); !slices.Equal(ids, test.want) {
+				t.Errorf("expected %v, got %v", test.want, ids)
+			}
+		})
+	}
+
+	t.Run("distances", func(t *testing.T) {
+		body := decodeJSON(t, serve(t, r, http.MethodGet, "/v1/place-names/london/nearby?radius_km=100", nil), http.StatusOK)
+		previous := 0.0
+		for _, result := range body["results"].([]any) {
+			distance, ok := result.(map[string]any)["distance_km"].(float64)
+			if !ok || distance < previous || distance > 100 {
+				t.Errorf("expected increasing distances within the radius, got %v", body["results"])
+			}
+			previous = distance
+		}
+	})
+
+	for name, test := range map[string]struct {
+		target string
+		status int
+		code   string
+	}{
+		"unknown id":      {"/v1/place-names/nowhere/nearby", http.StatusNotFound, "not_found"},
+		"no location":     {"/v1/place-names/luton/nearby", http.StatusUnprocessableEntity, "no_location"},
+		"radius too big":  {"/v1/place-names/london/nearby?radius_km=101", http.StatusBadRequest, "invalid_parameter"},
+		"unknown order":   {"/v1/place-names/london/nearby?order=alphabetical", http.StatusBadRequest, "invalid_parameter"},
+		"negative radius": {"/v1/place-names/london/nearby?radius_km=-1", http.StatusBadRequest, "invalid_parameter"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			problem := decodeJSON(t, serve(t, r, http.MethodGet, test.target, nil), test.status)
+			if problem["code"] != test.code {
+				t.Errorf("expected %s, got %v", test.code, problem)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package routes
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+	"github.com/map-services/placenames-api/pkg/api"
+)
+
+var (
+	//go:embed openapi.json
+	openAPISpec []byte
+
+	//go:embed swagger.html
+	swaggerUI []byte
+
+	// Swagger UI v5.29.1, from the swagger-api/swagger-ui release's dist
+	// directory, so the docs work offline and under a strict CSP
+	//go:embed swagger-ui/swagger-ui.css swagger-ui/swagger-ui-bundle.js swagger-ui/swagger-initializer.js
+	swaggerUIAssets embed.FS
+)
+
+// LoadOpenAPI parses and validates the embedded OpenAPI document.
+func LoadOpenAPI() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(openAPISpec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load OpenAPI document: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	return doc, nil
+}
+
+func OpenAPISpec(c *gin.Context) {
+	c.Data(http.StatusOK, gin.MIMEJSON, openAPISpec)
+}
+
+func SwaggerUI(c *gin.Context) {
+	c.Data(http.StatusOK, gin.MIMEHTML, swaggerUI)
+}
+
+// SwaggerUIAssets are the scripts and styles the Swagger UI page loads
+func SwaggerUIAssets() http.FileSystem {
+	assets, _ := fs.Sub(swaggerUIAssets, "swagger-ui")   // can't fail, the directory is embedded
+	return &gin.OnlyFilesFS{FileSystem: http.FS(assets)} // without a directory listing
+}
+
+// ValidateRequests rejects requests which don't match the OpenAPI document,
+// before they reach the handlers. Requests for routes the document doesn't
+// describe are passed through untouched.
+func ValidateRequests(doc *openapi3.T) (gin.HandlerFunc, error) {
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OpenAPI router: %w", err)
+	}
+	options := &openapi3filter.Options{
+		MultiError:          true,
+		SkipSettingDefaults: true, // the handlers apply their own defaults
+		AuthenticationFunc:  openapi3filter.NoopAuthenticationFunc,
+	}
+
+	// validating a body means reading all of it, which defeats streaming it
+	streamedOptions := *options
+	streamedOptions.ExcludeRequestBody = true
+
+	return func(c *gin.Context) {
+		route, pathParams, err := router.FindRoute(c.Request)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		routeOptions := options
+		if _, ok := route.Operation.Extensions["x-streamed-body"]; ok {
+			routeOptions = &streamedOptions
+		} else {
+			limitBody(c)
+		}
+		err = openapi3filter.ValidateRequest(c.Request.Context(), &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    routeOptions,
+		})
+		if tooLarge := (*http.MaxBytesError)(nil); errors.As(err, &tooLarge) {
+			bodyTooLarge(c, tooLarge)
+			return
+		}
+		if err != nil {
+			abortWithProblem(c, specProblem(err))
+			return
+		}
+		c.Next()
+	}, nil
+}
+
+// specProblem converts validation errors into a problem naming every
+// invalid parameter, or the request body.
+func specProblem(err error) api.Problem {
+	problem := api.Problem{
+		Status: http.StatusBadRequest,
+		Code:   api.CodeInvalidParameter,
+		Detail: "request does not match the API specification",
+	}
+
+	var errs openapi3.MultiError
+	if !errors.As(err, &errs) {
+		errs = openapi3.MultiError{err}
+	}
+	for _, err := range errs {
+		var reqErr *openapi3filter.RequestError
+		if !errors.As(err, &reqErr) {
+			problem.Detail = err.Error()
+			continue
+		}
+		// drop the schema & value dumps appended to schema errors
+		reason, _, _ := strings.Cut(reqErr.Error(), "\nSchema:")
+		switch {
+		case reqErr.Parameter != nil:
+			problem.InvalidParams = append(problem.InvalidParams, api.InvalidParam{Name: reqErr.Parameter.Name, Reason: reason})
+		case reqErr.RequestBody != nil:
+			problem.Code = api.CodeInvalidBody
+			problem.InvalidParams = append(problem.InvalidParams, api.InvalidParam{Name: "body", Reason: reason})
+		default:
+			problem.Detail = reqErr.Error()
+		}
+	}
+	return problem
+}
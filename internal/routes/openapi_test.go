@@ -0,0 +1,130 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/map-services/placenames-api/internal"
+	"github.com/map-services/placenames-api/pkg/api"
+)
+
+func TestOpenAPIDocument(t *testing.T) {
+	doc, err := LoadOpenAPI()
+	if err != nil {
+		t.Fatalf("expected a valid document, got %v", err)
+	}
+	if _, err := ValidateRequests(doc); err != nil {
+		t.Fatalf("expected a request validator, got %v", err)
+	}
+
+	t.Run("schemas match the response types", func(t *testing.T) {
+		for name, value := range map[string]any{
+			"PlaceResponse":    api.PlaceResponse{},
+			"Result":           api.Result{},
+			"ResponseMeta":     api.ResponseMeta{},
+			"PlaceDetail":      api.PlaceDetail{},
+			"Record":           Record{},
+			"MatchRow":         MatchRow{},
+			"LookupResponse":   api.LookupResponse{},
+			"DistanceResponse": api.DistanceResponse{},
+			"Problem":          api.Problem{},
+		} {
+			schema := doc.Components.Schemas[name]
+			if schema == nil {
+				t.Errorf("%s: missing from the document", name)
+				continue
+			}
+			checkSchemaFields(t, name, reflect.TypeOf(value), schema.Value)
+		}
+	})
+
+	t.Run("place response validates", func(t *testing.T) {
+		distance := 1.5
+		resp := api.PlaceResponse{
+			MatchType: api.MatchTypeCoordinate,
+			Results: []api.Result{
+				{ID: "IPN0001", Name: "London", Relevancy: 1, Type: internal.PlaceTypeCity, DistanceKm: &distance, GridRef: "TQ 300 803", Geohash: "gcpvj0e54"},
+				{ID: "IPN0002", Name: "Croydon", Relevancy: 0.7},
+			},
+			NextCursor: internal.EncodeCursor("51.5, -0.12", 2),
+			Meta: &api.ResponseMeta{
+				Query: "51.500000, -0.120000", Mode: api.ModeCoordinate, MaxResults: 2, Total: new(10),
+				Truncated: true, DatasetVersion: "sha256:297acc968544", TookMs: 0.08,
+			},
+		}
+		validateAgainst(t, doc, "PlaceResponse", resp)
+		validateAgainst(t, doc, "PlaceResponse", api.PlaceResponse{MatchType: api.MatchTypePrefix, Results: []api.Result{}})
+	})
+}
+
+// checkSchemaFields compares the JSON fields of a struct with the schema's
+// properties: every field must be described, and required properties must
+// always be present.
+func checkSchemaFields(t *testing.T, name string, typ reflect.Type, schema *openapi3.Schema) {
+	t.Helper()
+	fields := map[string]bool{}
+	for field := range typ.Fields() {
+		tag := field.Tag.Get("json")
+		if !field.IsExported() || tag == "" || tag == "-" {
+			continue
+		}
+		jsonName, opts, _ := strings.Cut(tag, ",")
+		omitEmpty := strings.Contains(opts, "omitempty")
+		fields[jsonName] = true
+
+		if _, ok := schema.Properties[jsonName]; !ok {
+			t.Errorf("%s: field %q is not in the schema", name, jsonName)
+		}
+		if omitEmpty && slices.Contains(schema.Required, jsonName) {
+			t.Errorf("%s: required property %q is omitempty", name, jsonName)
+		}
+	}
+	for property := range schema.Properties {
+		if !fields[property] {
+			t.Errorf("%s: schema property %q has no field", name, property)
+		}
+	}
+}
+
+func validateAgainst(t *testing.T, doc *openapi3.T, name string, value any) {
+	t.Helper()
+	body, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("failed to marshal %s: %v", name, err)
+	}
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal %s: %v", name, err)
+	}
+	if err := doc.Components.Schemas[name].Value.VisitJSON(decoded, openapi3.EnableJSONSchema2020()); err != nil {
+		t.Errorf("%s does not match the schema: %v\n%s", name, err, body)
+	}
+}
+
+func TestSwaggerUI(t *testing.T) {
+	r := newTestRouter(t)
+
+	page := serve(t, r, http.MethodGet, "/docs", nil)
+	if page.Code != http.StatusOK {
+		t.Fatalf("expected the docs page, got %d", page.Code)
+	}
+	if strings.Contains(page.Body.String(), "https://") {
+		t.Errorf("expected the docs page to load nothing from elsewhere, got %s", page.Body)
+	}
+
+	for asset, contentType := range map[string]string{
+		"swagger-ui.css":         "text/css",
+		"swagger-ui-bundle.js":   "text/javascript",
+		"swagger-initializer.js": "text/javascript",
+	} {
+		w := serve(t, r, http.MethodGet, "/docs/assets/"+asset, nil)
+		if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), contentType) || w.Body.Len() == 0 {
+			t.Errorf("%s: expected %s, got %d %s", asset, contentType, w.Code, w.Header().Get("Content-Type"))
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package routes
+
+import (
+	"cmp"
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/codec/json"
+	"github.com/map-services/placenames-api/internal"
+	"github.com/map-services/placenames-api/pkg/api"
+)
+
+// OpenSearch lets browsers add the place search to the address bar, with
+// suggestions as you type.
+// See https://github.com/dewitt/opensearch/blob/master/opensearch-1-1-draft-6.md
+
+const (
+	MIMEOpenSearchDescription = "application/opensearchdescription+xml"
+	MIMESuggestionsJSON       = "application/x-suggestions+json"
+)
+
+type openSearchDescription struct {
+	XMLName       xml.Name        `xml:"http://a9.com/-/spec/opensearch/1.1/ OpenSearchDescription"`
+	ShortName     string          `xml:"ShortName"`
+	Description   string          `xml:"Description"`
+	InputEncoding string          `xml:"InputEncoding"`
+	URLs          []openSearchURL `xml:"Url"`
+}
+
+type openSearchURL struct {
+	Type     string `xml:"type,attr"`
+	Rel      string `xml:"rel,attr,omitempty"`
+	Template string `xml:"template,attr"`
+}
+
+// OpenSearchDescription is the description document browsers discover the
+// search from. Its URLs must be absolute, so are built from the public URL.
+// There's no HTML results page, so only the JSON results are offered.
+func OpenSearchDescription(publicURL string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		base := baseURL(c, publicURL)
+		description := openSearchDescription{
+			ShortName:     "Place Names",
+			Description:   "Search the ONS Index of Place Names",
+			InputEncoding: "UTF-8",
+			URLs: []openSearchURL{
+				{Type: MIMESuggestionsJSON, Rel: "suggestions", Template: base + "/v1/suggest?q={searchTerms}"},
+				{Type: gin.MIMEJSON, Rel: "results", Template: base + "/v1/place-names?q={searchTerms}"},
+				{Type: MIMEOpenSearchDescription, Rel: "self", Template: base + "/opensearch.xml"},
+			},
+		}
+
+		body, err := xml.MarshalIndent(description, "", "  ")
+		if err != nil {
+			_ = c.Error(err)
+			abortWithStatus(c, http.StatusInternalServerError, api.CodeInternalError, "failed to render the OpenSearch description")
+			return
+		}
+		c.Data(http.StatusOK, MIMEOpenSearchDescription, append([]byte(xml.Header), body...))
+	}
+}
+
+// Suggest answers in the OpenSearch Suggestions format:
+// [query, [names], [descriptions], [urls]].
+func Suggest(trie *internal.Trie, publicURL string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query, ok := c.GetQuery("q")
+		if !ok {
+			badRequest(c, paramErrorf("q", "q is required"))
+			return
+		}
+		if utf8.RuneCountInString(query) > maxQueryLength {
+			badRequest(c, paramErrorf("q", "q must be no more than %d characters", maxQueryLength))
+			return
+		}
+		maxResults, err := parseMaxResults(c, trie.TopK())
+		if err != nil {
+			badRequest(c, err)
+			return
+		}
+
+		var places []*internal.Place
+		if strings.TrimSpace(query) != "" {
+			places = trie.FindByPrefix(query)
+			places = places[:min(maxResults, len(places))]
+		}
+
+		base := baseURL(c, publicURL)
+		names := make([]string, len(places))
+		descriptions := make([]string, len(places))
+		urls := make([]string, len(places))
+		for i, place := range places {
+			names[i] = applyPrefixCasing(place.Name, query)
+			descriptions[i] = describePlace(place)
+			urls[i] = base + "/v1/place-names/" + url.PathEscape(place.ID)
+		}
+
+		body, err := json.API.Marshal([]any{query, names, descriptions, urls})
+		if err != nil {
+			_ = c.Error(err)
+			abortWithStatus(c, http.StatusInternalServerError, api.CodeInternalError, "failed to render suggestions")
+			return
+		}
+		c.Data(http.StatusOK, MIMESuggestionsJSON, body)
+	}
+}
+
+// describePlace tells apart places with the same name, e.g. "Town in
+// Newport, Wales".
+func describePlace(place *internal.Place) string {
+	description := strings.Join(placeAreas(place), ", ")
+	if place.Type == "" {
+		return description
+	}
+
+	if description == "" {
+		return placeTypeName(place.Type)
+	}
+	return placeTypeName(place.Type) + " in " + description
+}
+
+// placeTypeName is the place type for display, e.g. "Built up area"
+func placeTypeName(placeType string) string {
+	name := []rune(strings.ReplaceAll(placeType, "_", " "))
+	if len(name) > 0 {
+		name[0] = unicode.ToUpper(name[0])
+	}
+	return string(name)
+}
+
+// placeAreas are the county (or local authority) and country the place is
+// in, whichever are known.
+func placeAreas(place *internal.Place) []string {
+	var areas []string
+	for _, name := range []string{cmp.Or(place.County, place.LocalAuthority), place.Country} {
+		if name != "" && !slices.Contains(areas, name) {
+			areas = append(areas, name)
+		}
+	}
+	return areas
+}
+
+// baseURL is the configured public URL, or failing that the scheme and host
+// the client reached us on, allowing for a TLS-terminating proxy. Those come
+// from the request's headers, so the response mustn't be cached where
+// other clients could be served it.
+func baseURL(c *gin.Context, publicURL string) string {
+	if publicURL != "" {
+		return publicURL
+	}
+	c.Header("Cache-Control", "no-store")
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host
+}
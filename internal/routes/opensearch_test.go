@@ -0,0 +1,51 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/map-services/placenames-api/internal"
+)
+
+func TestOpenSearch(t *testing.T) {
+	t.Run("public URL", func(t *testing.T) {
+		trie := internal.NewTrie(10)
+		r := gin.New()
+		if err := Register(r, trie, internal.NewSpatialIndex(nil, 0.1), "https://places.example.com/"); err != nil {
+			t.Fatalf("failed to register routes: %v", err)
+		}
+
+		w := serve(t, r, http.MethodGet, "/opensearch.xml", nil, "Host", "evil.example", "X-Forwarded-Proto", "http")
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body)
+		}
+		body := w.Body.String()
+		if strings.Contains(body, "evil.example") || !strings.Contains(body, `template="https://places.example.com/v1/suggest?q={searchTerms}"`) {
+			t.Errorf("expected the URLs to use the public URL, got %s", body)
+		}
+		if strings.Contains(body, gin.MIMEHTML) {
+			t.Errorf("expected no HTML results URL, got %s", body)
+		}
+	})
+
+	t.Run("from the request", func(t *testing.T) {
+		r := newTestRouter(t)
+		w := serve(t, r, http.MethodGet, "/v1/suggest?q=new", nil, "Host", "places.test", "X-Forwarded-Proto", "https")
+		if !strings.Contains(w.Body.String(), `"https://places.test/v1/place-names/newport-wales"`) {
+			t.Errorf("expected the URLs to use the request's host, got %s", w.Body)
+		}
+		if cacheControl := w.Header().Get("Cache-Control"); cacheControl != "no-store" {
+			t.Errorf("expected suggestions built from the request not to be cached, got Cache-Control: %s", cacheControl)
+		}
+	})
+
+	t.Run("invalid public URL", func(t *testing.T) {
+		for _, publicURL := range []string{"places.example.com", "ftp://places.example.com", "https://"} {
+			if err := Register(gin.New(), internal.NewTrie(10), internal.NewSpatialIndex(nil, 0.1), publicURL); err == nil {
+				t.Errorf("%s: expected an error", publicURL)
+			}
+		}
+	})
+}
@@ -0,0 +1,222 @@
+package routes
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/map-services/placenames-api/internal"
+	"github.com/map-services/placenames-api/pkg/api"
+)
+
+// SearchOptions are the request options shared by every search mode,
+// whichever route they arrive through.
+type SearchOptions struct {
+	Query      string
+	MaxResults int
+	Offset     int
+	Mode       string
+	Rank       string
+	Fields     fieldSet
+	Filter     internal.PlaceFilter
+	Envelope   bool
+
+	start time.Time
+}
+
+var searchModes = []string{api.ModeAuto, api.ModePrefix, api.ModeExact, api.ModeCoordinate, api.ModeGridRef}
+
+const (
+	maxQueryLength = 100
+
+	// bounds how far a cursor can walk into a subtree
+	maxCursorOffset = 10_000
+)
+
+func parseSearchOptions(c *gin.Context, query string, topK int) (SearchOptions, error) {
+	opts := SearchOptions{Query: query, start: time.Now()}
+	if utf8.RuneCountInString(query) > maxQueryLength {
+		return opts, paramErrorf("q", "q must be no more than %d characters", maxQueryLength)
+	}
+
+	var err error
+	if opts.MaxResults, err = parseMaxResults(c, topK); err != nil {
+		return opts, err
+	}
+	if opts.Mode, err = parseMode(c.Query("mode")); err != nil {
+		return opts, err
+	}
+	if opts.Rank, err = parseRank(c.Query("rank")); err != nil {
+		return opts, err
+	}
+	if opts.Fields, err = parseFields(c.Query("fields")); err != nil {
+		return opts, err
+	}
+	if opts.Filter, err = parseFilter(c); err != nil {
+		return opts, err
+	}
+	// after everything the cursor's search is keyed on
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		if c.Query("offset") != "" {
+			return opts, paramErrorf("cursor", "offset and cursor cannot be combined")
+		}
+		if opts.Offset, err = internal.DecodeCursor(cursor, opts.cursorKey()); err != nil || opts.Offset > maxCursorOffset {
+			return opts, paramErrorf("cursor", "cursor is not valid for this search")
+		}
+	} else if opts.Offset, err = parseOffset(c, opts.MaxResults, topK); err != nil {
+		return opts, err
+	}
+	if envelope := c.Query("envelope"); envelope != "" {
+		if opts.Envelope, err = strconv.ParseBool(envelope); err != nil {
+			return opts, paramErrorf("envelope", "envelope must be true or false")
+		}
+	}
+	return opts, nil
+}
+
+// cursorKey identifies the search a cursor pages through: the query, and
+// everything else which decides what is on each page.
+func (opts SearchOptions) cursorKey() string {
+	return strings.Join([]string{opts.Query, opts.Mode, opts.Rank, strconv.Itoa(opts.MaxResults), opts.Filter.Key()}, "\x00")
+}
+
+func parseMode(value string) (string, error) {
+	if value == "" {
+		return api.ModeAuto, nil
+	}
+	if !slices.Contains(searchModes, value) {
+		return "", paramErrorf("mode", "mode must be one of: %s", strings.Join(searchModes, ", "))
+	}
+	return value, nil
+}
+
+func parseOffset(c *gin.Context, maxResults, topK int) (int, error) {
+	offsetStr := c.Query("offset")
+	if offsetStr == "" {
+		return 0, nil
+	}
+	if offset, err := strconv.Atoi(offsetStr); err == nil && offset >= 0 && offset+maxResults <= topK {
+		return offset, nil
+	}
+	return 0, paramErrorf("offset", "offset must be a non-negative integer, with offset + max_results no more than %d", topK)
+}
+
+var optionalFields = []string{api.FieldGridRef, api.FieldGeohash}
+
+type fieldSet map[string]bool
+
+func parseFields(value string) (fieldSet, error) {
+	fields := fieldSet{}
+	if value == "" {
+		return fields, nil
+	}
+	for field := range strings.SplitSeq(value, ",") {
+		field = strings.TrimSpace(field)
+		if !slices.Contains(optionalFields, field) {
+			return nil, paramErrorf("fields", "fields must be a comma-separated list of: %s", strings.Join(optionalFields, ", "))
+		}
+		fields[field] = true
+	}
+	return fields, nil
+}
+
+const defaultMaxResults = 10
+
+func parseMaxResults(c *gin.Context, limit int) (int, error) {
+	maxStr := c.Query("max_results")
+	if maxStr == "" {
+		return min(defaultMaxResults, limit), nil
+	}
+	if max, err := strconv.Atoi(maxStr); err == nil && max > 0 && max <= limit {
+		return max, nil
+	}
+	return 0, paramErrorf("max_results", "max_results must be a positive integer less than or equal to %d", limit)
+}
+
+func parseRank(value string) (string, error) {
+	switch value {
+	case "", api.RankRelevancy:
+		return api.RankRelevancy, nil
+	case api.RankBlended:
+		return api.RankBlended, nil
+	default:
+		return "", paramErrorf("rank", "rank must be one of: %s, %s", api.RankRelevancy, api.RankBlended)
+	}
+}
+
+// parseFilter maps the hierarchy query parameters onto the ONS fields, and
+// the types & geohash parameters onto the place type and location
+func parseFilter(c *gin.Context) (internal.PlaceFilter, error) {
+	filter := internal.PlaceFilter{
+		Country:        strings.TrimSpace(c.Query("country")),
+		Region:         strings.TrimSpace(c.Query("region")),
+		County:         strings.TrimSpace(c.Query("county")),
+		LocalAuthority: strings.TrimSpace(c.Query("lad")),
+		NationalPark:   strings.TrimSpace(c.Query("npark")),
+	}
+	if types := c.Query("types"); types != "" {
+		for placeType := range strings.SplitSeq(types, ",") {
+			if normalized := internal.NormalizePlaceType(placeType); normalized != "" {
+				filter.Types = append(filter.Types, normalized)
+			}
+		}
+	}
+	if geohash := c.Query("geohash"); geohash != "" {
+		var err error
+		if filter.Geohash, err = internal.ParseGeohash(geohash); err != nil {
+			return filter, paramErrorf("geohash", "%v", err)
+		}
+	}
+	return filter, nil
+}
+
+// placeAttributes are the attributes places can be matched on by name, as by
+// Elasticsearch contexts and reconciliation properties
+var placeAttributes = map[string]func(*internal.Place) string{
+	"country":         func(p *internal.Place) string { return p.Country },
+	"region":          func(p *internal.Place) string { return p.Region },
+	"county":          func(p *internal.Place) string { return p.County },
+	"lad":             func(p *internal.Place) string { return p.LocalAuthority },
+	"local_authority": func(p *internal.Place) string { return p.LocalAuthority },
+	"npark":           func(p *internal.Place) string { return p.NationalPark },
+	"national_park":   func(p *internal.Place) string { return p.NationalPark },
+	"type":            func(p *internal.Place) string { return p.Type },
+	"place_type":      func(p *internal.Place) string { return p.Type },
+}
+
+// attributeIn accepts places whose named attribute is one of the values,
+// ignoring case (and for place types, how they're written).
+func attributeIn(name string, values []string) func(*internal.Place) bool {
+	attribute := placeAttributes[name]
+	if name == "type" || name == "place_type" {
+		normalized := make([]string, len(values))
+		for i, value := range values {
+			normalized[i] = internal.NormalizePlaceType(value)
+		}
+		values = normalized
+	}
+	return func(place *internal.Place) bool {
+		return slices.ContainsFunc(values, func(value string) bool {
+			return strings.EqualFold(value, attribute(place))
+		})
+	}
+}
+
+// allOf accepts places accepted by every predicate, and is nil (accepting
+// everything) when there are none.
+func allOf(predicates []func(*internal.Place) bool) func(*internal.Place) bool {
+	if len(predicates) == 0 {
+		return nil
+	}
+	return func(place *internal.Place) bool {
+		for _, predicate := range predicates {
+			if !predicate(place) {
+				return false
+			}
+		}
+		return true
+	}
+}
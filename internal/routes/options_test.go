@@ -0,0 +1,56 @@
+package routes
+
+import (
+	"github.com/map-services/placenames-api/pkg/api"
+	"net/http"
+	"testing"
+)
+
+func TestCursor(t *testing.T) {
+	r := newTestRouter(t)
+
+	// the first page of a search, and a cursor for the next
+	first := decodeJSON(t, serve(t, r, http.MethodGet, "/v1/place-names?q=l&max_results=1&country=england", nil), http.StatusOK)
+	cursor, _ := first["next_cursor"].(string)
+	if cursor == "" {
+		t.Fatalf("expected a next cursor, got %v", first)
+	}
+
+	t.Run("same search", func(t *testing.T) {
+		next := decodeJSON(t, serve(t, r, http.MethodGet, "/v1/place-names?q=l&max_results=1&country=England&cursor="+cursor, nil), http.StatusOK)
+		results, _ := next["results"].([]any)
+		if len(results) != 1 || results[0].(map[string]any)["id"] != "luton" {
+			t.Errorf("expected the second page to be Luton, got %v", next["results"])
+		}
+	})
+
+	t.Run("different search", func(t *testing.T) {
+		for name, query := range map[string]string{
+			"query":      "q=lo&max_results=1&country=england",
+			"filter":     "q=l&max_results=1",
+			"other area": "q=l&max_results=1&country=wales",
+			"types":      "q=l&max_results=1&country=england&types=town",
+			"mode":       "q=l&max_results=1&country=england&mode=exact",
+			"rank":       "q=l&max_results=1&country=england&rank=blended",
+			"page size":  "q=l&max_results=2&country=england",
+			"geohash":    "q=l&max_results=1&country=england&geohash=gcp",
+		} {
+			problem := decodeJSON(t, serve(t, r, http.MethodGet, "/v1/place-names?"+query+"&cursor="+cursor, nil), http.StatusBadRequest)
+			if problem["code"] != api.CodeInvalidParameter {
+				t.Errorf("%s: expected an invalid cursor, got %v", name, problem)
+			}
+		}
+	})
+}
+
+func TestEmptyQuery(t *testing.T) {
+	r := newTestRouter(t)
+
+	body := decodeJSON(t, serve(t, r, http.MethodGet, "/v1/place-names?q=", nil), http.StatusOK)
+	if ids := resultIDs(t, body); len(ids) != 0 {
+		t.Errorf("expected an empty query to match no places, got %v", ids)
+	}
+	if problem := decodeJSON(t, serve(t, r, http.MethodGet, "/v1/place-names", nil), http.StatusBadRequest); problem["code"] != "invalid_parameter" {
+		t.Errorf("expected a missing query to be invalid, got %v", problem)
+	}
+}
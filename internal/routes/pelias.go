@@ -0,0 +1,275 @@
+package routes
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/map-services/placenames-api/internal"
+)
+
+// Pelias compatibility: the parameters and GeoJSON response of Pelias's
+// autocomplete, so the Leaflet and MapLibre geocoder plugins written for it
+// can use this service as-is. Responses (and errors) follow Pelias's shapes
+// rather than ours.
+// See https://github.com/pelias/documentation/blob/master/autocomplete.md
+
+const (
+	defaultPeliasSize = 10
+	maxPeliasSize     = 40
+
+	peliasSource      = "placenames"
+	peliasAttribution = "https://geoportal.statistics.gov.uk/datasets/208d9884575647c29f0dd5a1184e711a/about"
+
+	// with a focus point, half the score comes from proximity, falling away
+	// over tens of kilometres as in Pelias
+	focusWeight  = 0.5
+	focusScaleKm = 50.0
+
+	// beyond this, proximity adds less than 1% to a score, so the places
+	// near the focus are only looked for this far away
+	focusRadiusKm = 5 * focusScaleKm
+)
+
+// peliasCountryCodes are the ISO 3166 codes covering the whole gazetteer
+var peliasCountryCodes = []string{"GB", "GBR", "UK"}
+
+// peliasLayers maps place types onto Pelias's layers; anything else is a
+// locality
+var peliasLayers = map[string]string{
+	internal.PlaceTypeSuburb: "neighbourhood",
+	"ward":                   "neighbourhood",
+	"parish":                 "localadmin",
+	"community":              "localadmin",
+	"national_park":          "venue",
+}
+
+type peliasResponse struct {
+	Geocoding peliasGeocoding `json:"geocoding"`
+	Type      string          `json:"type"`
+	Features  []Feature       `json:"features"`
+	BBox      []float64       `json:"bbox,omitempty"`
+}
+
+type peliasGeocoding struct {
+	Version     string         `json:"version"`
+	Attribution string         `json:"attribution"`
+	Query       map[string]any `json:"query"`
+	Errors      []string       `json:"errors,omitempty"`
+	Warnings    []string       `json:"warnings,omitempty"`
+	Timestamp   int64          `json:"timestamp"`
+}
+
+type peliasQuery struct {
+	text      string
+	size      int
+	focus     *[2]float64 // lat, long
+	countries []string    // nil for anywhere
+	warnings  []string
+}
+
+// PeliasAutocomplete is Pelias's /v1/autocomplete: text, size, focus.point
+// and boundary.country are understood, and other parameters ignored. Places
+// without a location are left out, as the plugins can't show them.
+func PeliasAutocomplete(trie *internal.Trie, spatial *internal.SpatialIndex) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		geocoding := peliasGeocoding{
+			Version:     "0.2",
+			Attribution: peliasAttribution,
+			Query:       map[string]any{},
+			Timestamp:   time.Now().UnixMilli(),
+		}
+		query, err := parsePeliasQuery(c)
+		if err != nil {
+			geocoding.Errors = []string{err.Error()}
+			c.AbortWithStatusJSON(http.StatusBadRequest, peliasResponse{Geocoding: geocoding, Type: "FeatureCollection", Features: []Feature{}})
+			return
+		}
+		geocoding.Warnings = query.warnings
+		geocoding.Query["text"] = query.text
+		geocoding.Query["size"] = query.size
+		if query.focus != nil {
+			geocoding.Query["focus.point.lat"], geocoding.Query["focus.point.lon"] = query.focus[0], query.focus[1]
+		}
+		if query.countries != nil {
+			geocoding.Query["boundary.country"] = query.countries
+		}
+
+		resp := peliasResponse{Geocoding: geocoding, Type: "FeatureCollection", Features: []Feature{}}
+		places, err := peliasCandidates(c.Request.Context(), trie, spatial, query)
+		if errors.Is(err, context.Canceled) {
+			c.Abort() // the client has gone
+			return
+		}
+		if err != nil {
+			resp.Geocoding.Errors = []string{err.Error()}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, resp)
+			return
+		}
+		for _, place := range places {
+			resp.Features = append(resp.Features, peliasFeature(place, query.focus))
+		}
+		if len(places) > 0 {
+			resp.BBox = boundingBox(places)
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func parsePeliasQuery(c *gin.Context) (peliasQuery, error) {
+	query := peliasQuery{text: strings.TrimSpace(c.Query("text")), size: defaultPeliasSize}
+	if query.text == "" {
+		return query, fmt.Errorf("invalid param 'text': text length, must be >0")
+	}
+	if utf8.RuneCountInString(query.text) > maxQueryLength {
+		return query, fmt.Errorf("invalid param 'text': text length, must be <=%d", maxQueryLength)
+	}
+
+	// like Pelias, clamp the size rather than reject it
+	if sizeStr := c.Query("size"); sizeStr != "" {
+		size, err := strconv.Atoi(sizeStr)
+		if err != nil {
+			return query, fmt.Errorf("invalid param 'size': must be an integer")
+		}
+		query.size = min(max(size, 1), maxPeliasSize)
+		if query.size != size {
+			query.warnings = append(query.warnings, fmt.Sprintf("out-of-range integer 'size', using %d", query.size))
+		}
+	}
+
+	latStr, longStr := c.Query("focus.point.lat"), c.Query("focus.point.lon")
+	if latStr != "" || longStr != "" {
+		if latStr == "" || longStr == "" {
+			return query, fmt.Errorf("parameters focus.point.lat and focus.point.lon must both be specified")
+		}
+		lat, err := strconv.ParseFloat(latStr, 64)
+		if err != nil || lat < -90 || lat > 90 {
+			return query, fmt.Errorf("invalid param 'focus.point.lat': must be a number between -90 and 90")
+		}
+		long, err := strconv.ParseFloat(longStr, 64)
+		if err != nil || long < -180 || long > 180 {
+			return query, fmt.Errorf("invalid param 'focus.point.lon': must be a number between -180 and 180")
+		}
+		query.focus = &[2]float64{lat, long}
+	}
+
+	if boundary := c.Query("boundary.country"); boundary != "" {
+		query.countries = []string{}
+		for country := range strings.SplitSeq(boundary, ",") {
+			country = strings.TrimSpace(country)
+			if country == "" {
+				return query, fmt.Errorf("invalid param 'boundary.country': must be a comma-separated list of countries")
+			}
+			if slices.Contains(peliasCountryCodes, strings.ToUpper(country)) {
+				query.countries = nil // everywhere
+				break
+			}
+			query.countries = append(query.countries, country)
+		}
+	}
+	return query, nil
+}
+
+// peliasCandidates are the best located places with the prefix in the
+// boundary countries (either our names for them, which narrow the search, or
+// other ISO codes, which match nothing), re-ranked by proximity to any focus.
+// A focus brings in the places with the prefix nearest to it, as well as the
+// most relevant, so that nearby small places can outrank distant big ones.
+func peliasCandidates(ctx context.Context, trie *internal.Trie, spatial *internal.SpatialIndex, query peliasQuery) ([]*internal.Place, error) {
+	accept := func(place *internal.Place) bool {
+		if !place.HasLocation() {
+			return false
+		}
+		return query.countries == nil || slices.ContainsFunc(query.countries, func(country string) bool {
+			return strings.EqualFold(country, place.Country)
+		})
+	}
+	if query.focus == nil {
+		return trie.FindByPrefixContext(ctx, query.text, query.size, accept)
+	}
+
+	places, err := trie.FindByPrefixContext(ctx, query.text, trie.TopK(), accept)
+	if err != nil {
+		return nil, err
+	}
+	prefix := strings.ToLower(query.text)
+	nearby := spatial.Nearest(query.focus[0], query.focus[1], trie.TopK(), focusRadiusKm, func(place *internal.Place) bool {
+		return strings.HasPrefix(strings.ToLower(place.Name), prefix) && accept(place)
+	})
+	for _, neighbour := range nearby {
+		if !slices.Contains(places, neighbour.Place) {
+			places = append(places, neighbour.Place)
+		}
+	}
+
+	scores := make(map[*internal.Place]float64, len(places))
+	for _, place := range places {
+		distance := internal.HaversineKm(query.focus[0], query.focus[1], place.Lat, place.Long)
+		scores[place] = (1-focusWeight)*place.Relevancy + focusWeight*math.Exp(-distance/focusScaleKm)
+	}
+	slices.SortStableFunc(places, func(a, b *internal.Place) int {
+		return cmp.Compare(scores[b], scores[a])
+	})
+	return places[:min(query.size, len(places))], nil
+}
+
+// peliasFeature has the properties the geocoder plugins use, with the ONS
+// hierarchy in Pelias's nearest equivalents
+func peliasFeature(place *internal.Place, focus *[2]float64) Feature {
+	layer, ok := peliasLayers[place.Type]
+	if !ok {
+		layer = "locality"
+	}
+	props := map[string]any{
+		"id":        place.ID,
+		"gid":       peliasSource + ":" + layer + ":" + place.ID,
+		"layer":     layer,
+		"source":    peliasSource,
+		"source_id": place.ID,
+		"name":      place.Name,
+		"country":   "United Kingdom",
+		"country_a": "GBR",
+		"accuracy":  "centroid",
+		"label":     strings.Join(append([]string{place.Name}, placeAreas(place)...), ", "),
+	}
+	for key, value := range map[string]string{
+		"macroregion": place.Country,
+		"region":      place.Region,
+		"county":      place.County,
+		"localadmin":  place.LocalAuthority,
+	} {
+		if value != "" {
+			props[key] = value
+		}
+	}
+	if layer == "locality" {
+		props["locality"] = place.Name
+	}
+	if focus != nil {
+		props["distance"] = math.Round(internal.HaversineKm(focus[0], focus[1], place.Lat, place.Long)*1000) / 1000
+	}
+	return Feature{
+		Type:       "Feature",
+		Geometry:   &Point{Type: "Point", Coordinates: []float64{place.Long, place.Lat}},
+		Properties: props,
+	}
+}
+
+// boundingBox is [min long, min lat, max long, max lat] of the places
+func boundingBox(places []*internal.Place) []float64 {
+	bbox := []float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for _, place := range places {
+		bbox[0], bbox[1] = min(bbox[0], place.Long), min(bbox[1], place.Lat)
+		bbox[2], bbox[3] = max(bbox[2], place.Long), max(bbox[3], place.Lat)
+	}
+	return bbox
+}
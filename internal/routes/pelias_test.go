@@ -0,0 +1,115 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/map-services/placenames-api/internal"
+)
+
+func TestPeliasAutocomplete(t *testing.T) {
+	r := newTestRouter(t)
+
+	t.Run("features", func(t *testing.T) {
+		w := serve(t, r, http.MethodGet, "/pelias/v1/autocomplete?text=new&size=50&focus.point.lat=51.5&focus.point.lon=-3", nil)
+		body := decodeJSON(t, w, http.StatusOK)
+		checkShape(t, "response", body, `{
+			"geocoding": {
+				"version": "0.2",
+				"attribution": "https://...",
+				"query": {"text": "new", "size": 40, "focus.point.lat": 51.5, "focus.point.lon": -3},
+				"warnings": ["out-of-range integer 'size', using 40"],
+				"timestamp": 0
+			},
+			"type": "FeatureCollection",
+			"features": [{
+				"type": "Feature",
+				"geometry": {"type": "Point", "coordinates": [-2.9977]},
+				"properties": {
+					"id": "newport-wales", "gid": "placenames:locality:newport-wales", "layer": "locality",
+					"source": "placenames", "source_id": "newport-wales", "name": "Newport", "locality": "Newport",
+					"country": "United Kingdom", "country_a": "GBR", "macroregion": "Wales", "accuracy": "centroid",
+					"label": "Newport, Wales", "distance": 9.364
+				}
+			}],
+			"bbox": [-2.9977]
+		}`)
+
+		features := body["features"].([]any)
+		if len(features) != 2 {
+			t.Fatalf("expected both Newports, got %v", features)
+		}
+		feature := features[0].(map[string]any)
+		if coordinates := feature["geometry"].(map[string]any)["coordinates"].([]any); len(coordinates) != 2 || coordinates[0] != -2.9977 {
+			t.Errorf("expected [long, lat], got %v", coordinates)
+		}
+		if props := feature["properties"].(map[string]any); props["gid"] != "placenames:locality:newport-wales" || props["label"] != "Newport, Wales" {
+			t.Errorf("expected the nearer Newport first, got %v", props)
+		}
+		if bbox := body["bbox"].([]any); len(bbox) != 4 || bbox[0] != -2.9977 || bbox[1] != 50.701 {
+			t.Errorf("expected [min long, min lat, max long, max lat], got %v", bbox)
+		}
+	})
+
+	t.Run("client gone", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/pelias/v1/autocomplete?text=new&boundary.country=Wales", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Body.Len() != 0 {
+			t.Errorf("expected the search to be abandoned, got %s", w.Body)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		w := serve(t, r, http.MethodGet, "/pelias/v1/autocomplete?text=", nil)
+		checkShape(t, "response", decodeJSON(t, w, http.StatusBadRequest), `{
+			"geocoding": {
+				"version": "0.2",
+				"attribution": "https://...",
+				"query": {},
+				"errors": ["invalid param 'text': text length, must be >0"],
+				"timestamp": 0
+			},
+			"type": "FeatureCollection",
+			"features": []
+		}`)
+	})
+}
+
+func TestPeliasFocus(t *testing.T) {
+	// only the two most relevant are kept at each trie node, so the nearby
+	// village has to be found through the spatial index
+	trie := internal.NewTrie(2)
+	for _, place := range []*internal.Place{
+		{ID: "barnsley", Name: "Barnsley", Relevancy: 0.9, Lat: 53.5526, Long: -1.4797, Country: "England"},
+		{ID: "barnet", Name: "Barnet", Relevancy: 0.8, Lat: 51.6526, Long: -0.2002, Country: "England"},
+		{ID: "barry", Name: "Barry", Relevancy: 0.2, Lat: 51.3998, Long: -3.2838, Country: "Wales"},
+	} {
+		trie.Insert(place)
+	}
+	r := gin.New()
+	if err := Register(r, trie, internal.NewSpatialIndex(trie.Places(), 0.1), ""); err != nil {
+		t.Fatalf("failed to register routes: %v", err)
+	}
+
+	w := serve(t, r, http.MethodGet, "/pelias/v1/autocomplete?text=bar&size=2&focus.point.lat=51.48&focus.point.lon=-3.18", nil)
+	features, _ := decodeJSON(t, w, http.StatusOK)["features"].([]any)
+	if len(features) != 2 {
+		t.Fatalf("expected 2 features, got %v", features)
+	}
+	first := features[0].(map[string]any)["properties"].(map[string]any)
+	if first["id"] != "barry" {
+		t.Errorf("expected Barry, next to the focus, to come first, got %v", first)
+	}
+
+	w = serve(t, r, http.MethodGet, "/pelias/v1/autocomplete?text=bar&size=2", nil)
+	features, _ = decodeJSON(t, w, http.StatusOK)["features"].([]any)
+	if len(features) != 2 || features[0].(map[string]any)["properties"].(map[string]any)["id"] != "barnsley" {
+		t.Errorf("expected the most relevant first without a focus, got %v", features)
+	}
+}
@@ -0,0 +1,103 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/map-services/placenames-api/internal"
+	"github.com/map-services/placenames-api/pkg/api"
+)
+
+const maxLookupIDs = 100
+
+func newPlaceDetail(place *internal.Place) api.PlaceDetail {
+	detail := api.PlaceDetail{
+		ID:             place.ID,
+		Name:           place.Name,
+		Relevancy:      place.Relevancy,
+		Type:           place.Type,
+		Country:        place.Country,
+		Region:         place.Region,
+		County:         place.County,
+		LocalAuthority: place.LocalAuthority,
+		NationalPark:   place.NationalPark,
+		GridRef:        place.GridRef(),
+		Geohash:        place.Geohash,
+	}
+	if place.HasLocation() {
+		detail.Lat, detail.Long = &place.Lat, &place.Long
+	}
+	if place.Easting != 0 || place.Northing != 0 {
+		detail.Easting, detail.Northing = &place.Easting, &place.Northing
+	}
+	return detail
+}
+
+func (detail placeDetail) body() any {
+	return api.PlaceDetail(detail)
+}
+
+func (detail placeDetail) geoJSON() any {
+	props := map[string]any{
+		"id":        detail.ID,
+		"name":      detail.Name,
+		"relevancy": detail.Relevancy,
+	}
+	if detail.GridRef != "" {
+		props["grid_ref"] = detail.GridRef
+	}
+	if detail.Geohash != "" {
+		props["geohash"] = detail.Geohash
+	}
+	return recordFeature(detail.record(), props)
+}
+
+func (resp lookupList) body() any {
+	return api.LookupResponse(resp)
+}
+
+func (resp lookupList) geoJSON() any {
+	features := make([]Feature, len(resp.Results))
+	for i, detail := range resp.Results {
+		features[i] = placeDetail(detail).geoJSON().(Feature)
+	}
+	return FeatureCollection{Type: "FeatureCollection", Features: features}
+}
+
+func PlaceByID(trie *internal.Trie) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		place, ok := trie.FindByID(id)
+		if !ok {
+			abortWithStatus(c, http.StatusNotFound, api.CodeNotFound, fmt.Sprintf("no place found with id: %s", id))
+			return
+		}
+		respond(c, placeDetail(newPlaceDetail(place)))
+	}
+}
+
+func Lookup(trie *internal.Trie) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req api.LookupRequest
+		limitBody(c)
+		if err := c.ShouldBindJSON(&req); err != nil {
+			invalidBody(c, err)
+			return
+		}
+		if len(req.IDs) > maxLookupIDs {
+			badRequest(c, paramErrorf("ids", "ids must contain no more than %d entries", maxLookupIDs))
+			return
+		}
+
+		resp := api.LookupResponse{Results: []api.PlaceDetail{}, NotFound: []string{}}
+		for _, id := range req.IDs {
+			if place, ok := trie.FindByID(id); ok {
+				resp.Results = append(resp.Results, newPlaceDetail(place))
+			} else {
+				resp.NotFound = append(resp.NotFound, id)
+			}
+		}
+		respond(c, lookupList(resp))
+	}
+}
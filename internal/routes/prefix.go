@@ -1,22 +1,34 @@
 package routes
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"strconv"
+	"slices"
+	"strings"
+	"time"
 	"unicode"
 
 	"github.com/gin-gonic/gin"
 	"github.com/map-services/placenames-api/internal"
+	"github.com/map-services/placenames-api/pkg/api"
 )
 
-type Result struct {
-	Name      string  `json:"name"`
-	Relevancy float64 `json:"relevancy"`
-}
-
-type PlaceResponse struct {
-	Results []Result `json:"results"`
+func newResult(place *internal.Place, name string, fields fieldSet) api.Result {
+	result := api.Result{
+		ID:        place.ID,
+		Name:      name,
+		Relevancy: place.Relevancy,
+		Type:      place.Type,
+	}
+	if fields[api.FieldGridRef] {
+		result.GridRef = place.GridRef()
+	}
+	if fields[api.FieldGeohash] {
+		result.Geohash = place.Geohash
+	}
+	return result
 }
 
 func applyPrefixCasing(candidate string, prefix string) string {
@@ -37,32 +49,180 @@ func applyPrefixCasing(candidate string, prefix string) string {
 	return string(result)
 }
 
-func Prefix(trie *internal.Trie) gin.HandlerFunc {
+func Prefix(trie *internal.Trie, spatial *internal.SpatialIndex) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		opts, err := parseSearchOptions(c, c.Param("query"), trie.TopK())
+		if err != nil {
+			badRequest(c, err)
+			return
+		}
+		search(c, trie, spatial, opts)
+	}
+}
+
+// PlaceNames is the query-parameter equivalent of Prefix, which avoids
+// awkward path encoding of slashes, leading spaces and empty queries.
+func PlaceNames(trie *internal.Trie, spatial *internal.SpatialIndex) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		query := c.Param("query")
-		maxResults := 10
-		if maxStr := c.Query("max_results"); maxStr != "" {
-			if max, err := strconv.Atoi(maxStr); err == nil && max > 0 && max <= trie.TopK() {
-				maxResults = max
-			} else {
-				c.JSON(http.StatusBadRequest, gin.H{
-					"error": fmt.Sprintf("max_results must be a positive integer less than or equal to %d", trie.TopK()),
-				})
-				return
-			}
+		query, ok := c.GetQuery("q")
+		if !ok {
+			badRequest(c, paramErrorf("q", "q is required"))
+			return
+		}
+		opts, err := parseSearchOptions(c, query, trie.TopK())
+		if err != nil {
+			badRequest(c, err)
+			return
+		}
+		search(c, trie, spatial, opts)
+	}
+}
+
+func search(c *gin.Context, trie *internal.Trie, spatial *internal.SpatialIndex, opts SearchOptions) {
+	resp, err := findPlaces(c.Request.Context(), trie, spatial, opts)
+	if errors.Is(err, context.Canceled) {
+		c.Abort() // the client has gone
+		return
+	}
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	respond(c, placeList{resp, trie})
+}
+
+// findPlaces runs the search, returning a ParamError or Problem if the query
+// can't be interpreted in the requested mode.
+func findPlaces(ctx context.Context, trie *internal.Trie, spatial *internal.SpatialIndex, opts SearchOptions) (api.PlaceResponse, error) {
+	mode := opts.Mode
+	if mode == api.ModeAuto {
+		mode = detectMode(opts.Query)
+	}
+	accept := opts.Filter.Predicate()
+	limit := opts.Offset + opts.MaxResults + 1 // one extra, to tell if there's a next page
+
+	var resp api.PlaceResponse
+	meta := api.ResponseMeta{Mode: mode, MaxResults: opts.MaxResults, Offset: opts.Offset}
+
+	switch mode {
+	case api.ModeWhat3Words:
+		return resp, &api.Problem{
+			Status:        http.StatusBadRequest,
+			Code:          api.CodeUnsupportedQuery,
+			Detail:        "what3words addresses are not supported",
+			InvalidParams: []api.InvalidParam{{Name: "q", Reason: "what3words addresses are not supported"}},
+		}
+
+	case api.ModeCoordinate:
+		lat, long, err := internal.ParseCoordinates(opts.Query)
+		if err != nil {
+			return resp, paramErrorf("q", "%v", err)
 		}
+		meta.Query = fmt.Sprintf("%.6f, %.6f", lat, long)
+		results := nearestResults(spatial, lat, long, limit, accept, opts.Fields)
+		resp = opts.page(api.MatchTypeCoordinate, results)
 
-		matches := trie.FindByPrefix(query)
-		maxResults = min(maxResults, len(matches))
+	case api.ModeGridRef:
+		easting, northing, err := internal.ParseGridRef(opts.Query)
+		if err != nil {
+			return resp, paramErrorf("q", "%v", err)
+		}
+		meta.Query = internal.FormatGridRef(easting, northing, 10)
+		lat, long := internal.OSGridToWGS84(easting, northing)
+		results := nearestResults(spatial, lat, long, limit, accept, opts.Fields)
+		resp = opts.page(api.MatchTypeGridRef, results)
+
+	case api.ModeExact:
+		matches := trie.FindByName(opts.Query)
+		matches = slices.DeleteFunc(matches, func(place *internal.Place) bool {
+			return accept != nil && !accept(place)
+		})
+		rankPlaces(matches, opts.Rank)
+		meta.Query = strings.ToLower(opts.Query)
+		meta.Total = new(len(matches))
+		results := placeResults(matches[:min(limit, len(matches))], "", opts.Fields)
+		resp = opts.page(api.MatchTypeExact, results)
 
-		results := make([]Result, maxResults)
-		for i, match := range matches[:maxResults] {
-			results[i] = Result{
-				Name:      applyPrefixCasing(match.Name, query),
-				Relevancy: match.Relevancy,
-			}
+	default:
+		var matches []*internal.Place
+		var err error
+		if opts.Rank == api.RankBlended {
+			// re-rank the best candidates by blending in the place type
+			matches, err = trie.FindByPrefixContext(ctx, opts.Query, trie.TopK(), accept)
+			rankPlaces(matches, opts.Rank)
+			matches = matches[:min(limit, len(matches))]
+		} else {
+			matches, err = trie.FindByPrefixContext(ctx, opts.Query, limit, accept)
 		}
+		if err != nil {
+			return resp, err
+		}
+		meta.Query = strings.ToLower(opts.Query)
+		meta.Total = new(trie.CountPrefix(opts.Query))
+		results := placeResults(matches, opts.Query, opts.Fields)
+		resp = opts.page(api.MatchTypePrefix, results)
+	}
+
+	if opts.Envelope {
+		meta.Truncated = resp.NextCursor != ""
+		meta.DatasetVersion = trie.Version()
+		meta.TookMs = float64(time.Since(opts.start).Microseconds()) / 1000
+		resp.Meta = &meta
+	}
+	return resp, nil
+}
+
+// detectMode works out how to interpret a free-text query
+func detectMode(query string) string {
+	if internal.LooksLikeWhat3Words(query) {
+		return api.ModeWhat3Words
+	}
+	if _, _, err := internal.ParseCoordinates(query); !errors.Is(err, internal.ErrNotCoordinates) {
+		return api.ModeCoordinate // even if invalid, so the error gets reported
+	}
+	if _, _, err := internal.ParseGridRef(query); err == nil {
+		return api.ModeGridRef
+	}
+	return api.ModePrefix
+}
+
+func rankPlaces(places []*internal.Place, rank string) {
+	if rank == api.RankBlended {
+		internal.SortByBlendedScore(places)
+	} else {
+		internal.SortByRelevancy(places)
+	}
+}
+
+// page trims the results fetched up to the end of the requested page (plus
+// one) down to that page, adding a cursor if there are more to come.
+func (opts SearchOptions) page(matchType string, results []api.Result) api.PlaceResponse {
+	resp := api.PlaceResponse{MatchType: matchType, Results: results[min(opts.Offset, len(results)):]}
+	if len(resp.Results) > opts.MaxResults {
+		resp.Results = resp.Results[:opts.MaxResults]
+		resp.NextCursor = internal.EncodeCursor(opts.cursorKey(), opts.Offset+opts.MaxResults)
+	}
+	return resp
+}
+
+func placeResults(places []*internal.Place, query string, fields fieldSet) []api.Result {
+	results := make([]api.Result, len(places))
+	for i, place := range places {
+		results[i] = newResult(place, applyPrefixCasing(place.Name, query), fields)
+	}
+	return results
+}
+
+func nearestResults(spatial *internal.SpatialIndex, lat, long float64, maxResults int, accept func(*internal.Place) bool, fields fieldSet) []api.Result {
+	return neighbourResults(spatial.Nearest(lat, long, maxResults, 0, accept), fields)
+}
 
-		c.JSON(http.StatusOK, PlaceResponse{Results: results})
+func neighbourResults(neighbours []internal.Neighbour, fields fieldSet) []api.Result {
+	results := make([]api.Result, len(neighbours))
+	for i, n := range neighbours {
+		dist := n.DistanceKm
+		results[i] = newResult(n.Place, n.Place.Name, fields)
+		results[i].DistanceKm = &dist
 	}
+	return results
 }
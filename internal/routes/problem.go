@@ -0,0 +1,142 @@
+package routes
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/map-services/placenames-api/pkg/api"
+)
+
+// Errors are returned as RFC 7807 problem details (see api.Problem), as are
+// validation failures.
+
+const MIMEProblemJSON = "application/problem+json"
+
+const problemTypePrefix = "urn:placenames:problem:"
+
+// ParamError is a validation failure for a single request parameter (or
+// request body field).
+type ParamError struct {
+	Name   string
+	Reason string
+}
+
+func (e *ParamError) Error() string {
+	return e.Reason
+}
+
+func paramErrorf(name, format string, args ...any) error {
+	return &ParamError{Name: name, Reason: fmt.Sprintf(format, args...)}
+}
+
+// newProblem fills in the standard members
+func newProblem(c *gin.Context, problem api.Problem) api.Problem {
+	problem.Type = problemTypePrefix + problem.Code
+	problem.Title = http.StatusText(problem.Status)
+	problem.Instance = c.Request.URL.Path
+	problem.RequestID = c.GetString(requestIDKey)
+	return problem
+}
+
+// abortWithProblem writes the problem, aborting any remaining handlers.
+// Problems are never cached: each carries its own request ID, and the
+// request may well succeed next time.
+func abortWithProblem(c *gin.Context, problem api.Problem) {
+	problem = newProblem(c, problem)
+	c.Header("Content-Type", MIMEProblemJSON)
+	c.Header("Cache-Control", "no-store")
+	c.AbortWithStatusJSON(problem.Status, problem)
+}
+
+func abortWithStatus(c *gin.Context, status int, code, detail string) {
+	abortWithProblem(c, api.Problem{Status: status, Code: code, Detail: detail})
+}
+
+// errorProblem describes a validation error, naming the offending parameter
+// when known. Errors which are already problems are returned as is.
+func errorProblem(err error) api.Problem {
+	var problem *api.Problem
+	if errors.As(err, &problem) {
+		return *problem
+	}
+	result := api.Problem{Status: http.StatusBadRequest, Code: api.CodeInvalidParameter, Detail: err.Error()}
+	var paramErr *ParamError
+	if errors.As(err, &paramErr) {
+		result.InvalidParams = []api.InvalidParam{{Name: paramErr.Name, Reason: paramErr.Reason}}
+	}
+	return result
+}
+
+func badRequest(c *gin.Context, err error) {
+	abortWithProblem(c, errorProblem(err))
+}
+
+// maxBodyBytes limits the request bodies read whole, which is all but the
+// streamed match input
+const maxBodyBytes = 1 << 20
+
+// limitBody stops reading the request body past maxBodyBytes; reading any
+// more fails with an *http.MaxBytesError, reported as a 413.
+func limitBody(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBodyBytes)
+}
+
+func bodyTooLarge(c *gin.Context, err *http.MaxBytesError) {
+	abortWithStatus(c, http.StatusRequestEntityTooLarge, api.CodeBodyTooLarge,
+		fmt.Sprintf("the request body must be no more than %d bytes", err.Limit))
+}
+
+func invalidBody(c *gin.Context, err error) {
+	if tooLarge := (*http.MaxBytesError)(nil); errors.As(err, &tooLarge) {
+		bodyTooLarge(c, tooLarge)
+		return
+	}
+	abortWithStatus(c, http.StatusBadRequest, api.CodeInvalidBody, fmt.Sprintf("invalid request body: %v", err))
+}
+
+// Recovery is used with gin.CustomRecovery, so panics are reported as
+// problems rather than a bare 500.
+func Recovery(c *gin.Context, err any) {
+	abortWithStatus(c, http.StatusInternalServerError, api.CodeInternalError, "an unexpected error occurred")
+}
+
+func NoRoute(c *gin.Context) {
+	abortWithStatus(c, http.StatusNotFound, api.CodeRouteNotFound, fmt.Sprintf("no route for %s", c.Request.URL.Path))
+}
+
+func NoMethod(c *gin.Context) {
+	abortWithStatus(c, http.StatusMethodNotAllowed, api.CodeMethodNotAllowed,
+		fmt.Sprintf("%s is not allowed for %s", c.Request.Method, c.Request.URL.Path))
+}
+
+const (
+	HeaderRequestID = "X-Request-ID"
+	requestIDKey    = "request_id"
+)
+
+var requestIDRegex = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID propagates a well-formed X-Request-ID header, or generates a new
+// one, so problems can be correlated with the server logs.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(HeaderRequestID)
+		if !requestIDRegex.MatchString(id) {
+			id = newRequestID()
+		}
+		c.Set(requestIDKey, id)
+		c.Header(HeaderRequestID, id)
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
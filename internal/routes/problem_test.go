@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestProblem(t *testing.T) {
+	r := newTestRouter(t)
+
+	for name, target := range map[string]string{
+		"invalid parameter": "/v1/place-names?q=lon&max_results=0",
+		"not found":         "/v1/place-names/nowhere",
+		"no route":          "/v2/place-names",
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := serve(t, r, http.MethodGet, target, nil, HeaderRequestID, "test-request")
+			if contentType := w.Header().Get("Content-Type"); contentType != MIMEProblemJSON {
+				t.Errorf("expected %s, got %s", MIMEProblemJSON, contentType)
+			}
+			if cacheControl := w.Header().Get("Cache-Control"); cacheControl != "no-store" {
+				t.Errorf("expected a problem not to be cached, got Cache-Control: %s", cacheControl)
+			}
+			problem := decodeJSON(t, w, w.Code)
+			if problem["request_id"] != "test-request" || problem["status"] != float64(w.Code) {
+				t.Errorf("expected the request ID and status, got %v", problem)
+			}
+		})
+	}
+}
+
+func TestBodyTooLarge(t *testing.T) {
+	r := newTestRouter(t)
+
+	for name, test := range map[string]struct{ target, body string }{
+		"lookup": {"/v1/place-names/lookup", `{"ids": ["` + strings.Repeat("a", maxBodyBytes) + `"]}`},
+		"search": {"/v1/place-names/search", `{"prefix": "` + strings.Repeat("a", maxBodyBytes) + `", "geometry": {}}`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := serve(t, r, http.MethodPost, test.target, strings.NewReader(test.body), "Content-Type", "application/json")
+			problem := decodeJSON(t, w, http.StatusRequestEntityTooLarge)
+			if problem["code"] != "body_too_large" {
+				t.Errorf("expected body_too_large, got %v", problem)
+			}
+		})
+	}
+}
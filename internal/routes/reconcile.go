@@ -0,0 +1,386 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/codec/json"
+	"github.com/map-services/placenames-api/internal"
+	"github.com/map-services/placenames-api/pkg/api"
+)
+
+// Reconciliation lets OpenRefine (and other clients of the W3C
+// Reconciliation Service API) match columns of messy place names against
+// the gazetteer in bulk.
+// See https://www.w3.org/community/reports/reconciliation/CG-FINAL-specs-0.2-20230410/
+
+const (
+	defaultReconcileLimit = 3
+	maxReconcileQueries   = 500
+	reconcileSuggestPage  = 10
+
+	// every place is a place, whatever its more specific type
+	reconcilePlaceType = "place"
+)
+
+// reconcileProperties are the properties a query can give to narrow down its
+// candidates, as ids for placeAttributes
+var reconcileProperties = []reconcileEntity{
+	{ID: "country", Name: "Country"},
+	{ID: "region", Name: "Region"},
+	{ID: "county", Name: "County"},
+	{ID: "lad", Name: "Local authority"},
+	{ID: "npark", Name: "National park"},
+}
+
+type reconcileManifest struct {
+	Versions        []string                           `json:"versions"`
+	Name            string                             `json:"name"`
+	IdentifierSpace string                             `json:"identifierSpace"`
+	SchemaSpace     string                             `json:"schemaSpace"`
+	DefaultTypes    []reconcileEntity                  `json:"defaultTypes"`
+	View            reconcileView                      `json:"view"`
+	Preview         reconcilePreview                   `json:"preview"`
+	Suggest         map[string]reconcileSuggestService `json:"suggest"`
+}
+
+type reconcileView struct {
+	URL string `json:"url"`
+}
+
+type reconcilePreview struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+type reconcileSuggestService struct {
+	ServiceURL  string `json:"service_url"`
+	ServicePath string `json:"service_path"`
+}
+
+// reconcileEntity is a place, type or property as the API refers to them
+type reconcileEntity struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type reconcileQuery struct {
+	Query      string              `json:"query"`
+	Type       any                 `json:"type"` // an id, or a list of them
+	Limit      int                 `json:"limit"`
+	Properties []reconcileProperty `json:"properties"`
+}
+
+type reconcileProperty struct {
+	PID string `json:"pid"`
+	V   any    `json:"v"` // a string, an entity, or a list of them
+}
+
+type reconcileResult struct {
+	Result []reconcileCandidate `json:"result"`
+}
+
+type reconcileCandidate struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Type        []reconcileEntity `json:"type"`
+	Score       float64           `json:"score"` // 0 to 100
+	Match       bool              `json:"match"` // whether it's safe to match automatically
+}
+
+type reconcileSuggestions struct {
+	Result []reconcileEntity `json:"result"`
+}
+
+// parseForm parses the query and any form body up front, as gin does so
+// lazily and drops the error, leaving an unreadable form looking empty.
+func parseForm(req *http.Request) error {
+	if err := req.ParseForm(); err != nil {
+		return err
+	}
+	if err := req.ParseMultipartForm(maxBodyBytes); !errors.Is(err, http.ErrNotMultipart) {
+		return err
+	}
+	return nil
+}
+
+// Reconcile serves the manifest describing the service, or reconciles a
+// batch of queries (as a JSON object in the queries parameter, by GET or
+// form POST). JSONP callbacks are supported for older clients.
+func Reconcile(trie *internal.Trie, matcher *internal.Matcher, publicURL string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limitBody(c)
+		if err := parseForm(c.Request); err != nil {
+			invalidBody(c, err)
+			return
+		}
+		if _, ok := c.GetPostForm("extend"); ok {
+			badRequest(c, paramErrorf("extend", "data extension is not supported"))
+			return
+		}
+		queries, ok := c.GetPostForm("queries")
+		if !ok {
+			queries, ok = c.GetQuery("queries")
+		}
+		if !ok {
+			if c.Request.Method != http.MethodGet {
+				badRequest(c, paramErrorf("queries", "queries is required"))
+				return
+			}
+			c.JSONP(http.StatusOK, reconcileManifestFor(baseURL(c, publicURL)))
+			return
+		}
+
+		var batch map[string]reconcileQuery
+		if err := json.API.Unmarshal([]byte(queries), &batch); err != nil {
+			badRequest(c, paramErrorf("queries", "queries must be a JSON object of queries: %v", err))
+			return
+		}
+		if len(batch) > maxReconcileQueries {
+			badRequest(c, paramErrorf("queries", "no more than %d queries can be reconciled at once", maxReconcileQueries))
+			return
+		}
+
+		results := make(map[string]reconcileResult, len(batch))
+		for key, query := range batch {
+			candidates, err := reconcile(matcher, query, trie.TopK())
+			if err != nil {
+				badRequest(c, paramErrorf("queries", "%s: %v", key, err))
+				return
+			}
+			results[key] = reconcileResult{Result: candidates}
+		}
+		c.JSONP(http.StatusOK, results)
+	}
+}
+
+func reconcileManifestFor(base string) reconcileManifest {
+	suggest := func(path string) reconcileSuggestService {
+		return reconcileSuggestService{ServiceURL: base, ServicePath: path}
+	}
+	return reconcileManifest{
+		Versions:        []string{"0.2"},
+		Name:            "Place Names",
+		IdentifierSpace: base + "/v1/place-names/",
+		SchemaSpace:     base + "/openapi.json#/components/schemas/",
+		DefaultTypes:    []reconcileEntity{{ID: reconcilePlaceType, Name: "Place"}},
+		View:            reconcileView{URL: base + "/v1/place-names/{{id}}"},
+		Preview:         reconcilePreview{URL: base + "/reconcile/preview?id={{id}}", Width: 400, Height: 90},
+		Suggest: map[string]reconcileSuggestService{
+			"entity":   suggest("/reconcile/suggest/entity"),
+			"type":     suggest("/reconcile/suggest/type"),
+			"property": suggest("/reconcile/suggest/property"),
+		},
+	}
+}
+
+// reconcile finds the candidates for a query. Its type and properties narrow
+// down the candidates, rather than adding to their scores. The best is only
+// a match if it's the one place with the name (allowing for normalization).
+func reconcile(matcher *internal.Matcher, query reconcileQuery, topK int) ([]reconcileCandidate, error) {
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultReconcileLimit
+	}
+	if limit < 0 || limit > topK {
+		return nil, fmt.Errorf("limit must be between 1 and %d", topK)
+	}
+
+	var predicates []func(*internal.Place) bool
+	types, err := reconcileValues(query.Type)
+	if err != nil {
+		return nil, fmt.Errorf("type %w", err)
+	}
+	if types = slices.DeleteFunc(types, func(id string) bool { return id == reconcilePlaceType }); len(types) > 0 {
+		predicates = append(predicates, attributeIn("type", types))
+	}
+	for _, property := range query.Properties {
+		if !slices.ContainsFunc(reconcileProperties, func(p reconcileEntity) bool { return p.ID == property.PID }) {
+			return nil, fmt.Errorf("unknown property: %s", property.PID)
+		}
+		values, err := reconcileValues(property.V)
+		if err != nil {
+			return nil, fmt.Errorf("property %s %w", property.PID, err)
+		}
+		if len(values) > 0 {
+			predicates = append(predicates, attributeIn(property.PID, values))
+		}
+	}
+
+	candidates := make([]reconcileCandidate, 0, limit)
+	if utf8.RuneCountInString(query.Query) > maxQueryLength {
+		return candidates, nil // not a place name, but not worth failing the batch over
+	}
+	// at least two, to tell if the best is the only good match
+	matches := matcher.Match(query.Query, max(limit, 2), allOf(predicates))
+	for i, match := range matches[:min(limit, len(matches))] {
+		place := match.Place
+		candidate := reconcileCandidate{
+			ID:          place.ID,
+			Name:        place.Name,
+			Description: describePlace(place),
+			Type:        []reconcileEntity{{ID: reconcilePlaceType, Name: "Place"}},
+			Score:       math.Round(match.Score*1000) / 10,
+		}
+		if place.Type != "" {
+			candidate.Type = slices.Insert(candidate.Type, 0, reconcileEntity{ID: place.Type, Name: placeTypeName(place.Type)})
+		}
+		if i == 0 && match.Type != internal.MatchFuzzy {
+			candidate.Match = len(matches) == 1 || matches[1].Type == internal.MatchFuzzy
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, nil
+}
+
+// reconcileValues flattens a type or property value: a string, an entity
+// (of which the name is used, or failing that its id), or a list of them.
+func reconcileValues(value any) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case map[string]any:
+		for _, key := range []string{"name", "id"} {
+			if s, ok := v[key].(string); ok {
+				return []string{s}, nil
+			}
+		}
+	case []any:
+		var values []string
+		for _, item := range v {
+			itemValues, err := reconcileValues(item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, itemValues...)
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("must be a string, an entity or a list of them")
+}
+
+var reconcilePreviewTemplate = template.Must(template.New("preview").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Name}}</title></head>
+<body style="margin: 0; font-family: sans-serif; font-size: 0.9em">
+<a href="{{.URL}}" target="_blank"><strong>{{.Name}}</strong></a>
+{{with .Description}}<p style="margin: 0.3em 0">{{.}}</p>{{end}}
+{{with .Location}}<p style="margin: 0; color: #666">{{.}}</p>{{end}}
+</body>
+</html>
+`))
+
+// ReconcilePreview is the snippet OpenRefine shows when hovering over a
+// candidate.
+func ReconcilePreview(trie *internal.Trie) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Query("id")
+		place, ok := trie.FindByID(id)
+		if !ok {
+			abortWithStatus(c, http.StatusNotFound, api.CodeNotFound, fmt.Sprintf("no place found with id: %s", id))
+			return
+		}
+
+		detail := newPlaceDetail(place)
+		var location string
+		if detail.Lat != nil {
+			location = fmt.Sprintf("%.5f, %.5f", *detail.Lat, *detail.Long)
+		}
+		if detail.GridRef != "" {
+			location = strings.TrimPrefix(location+" ("+detail.GridRef+")", " ")
+		}
+
+		var body strings.Builder
+		err := reconcilePreviewTemplate.Execute(&body, map[string]string{
+			"Name":        place.Name,
+			"Description": describePlace(place),
+			"Location":    location,
+			"URL":         "/v1/place-names/" + url.PathEscape(place.ID), // the preview is framed by our own page
+		})
+		if err != nil {
+			_ = c.Error(err)
+			abortWithStatus(c, http.StatusInternalServerError, api.CodeInternalError, "failed to render the preview")
+			return
+		}
+		c.Data(http.StatusOK, gin.MIMEHTML+"; charset=utf-8", []byte(body.String()))
+	}
+}
+
+// ReconcileSuggestEntity suggests places by prefix, for choosing a match by
+// hand. The cursor is the number of suggestions already seen.
+func ReconcileSuggestEntity(trie *internal.Trie) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cursor := 0
+		if cursorStr := c.Query("cursor"); cursorStr != "" {
+			var err error
+			if cursor, err = strconv.Atoi(cursorStr); err != nil || cursor < 0 || cursor > maxCursorOffset {
+				badRequest(c, paramErrorf("cursor", "cursor must be an integer between 0 and %d", maxCursorOffset))
+				return
+			}
+		}
+
+		suggestions := reconcileSuggestions{Result: []reconcileEntity{}}
+		if prefix := strings.TrimSpace(c.Query("prefix")); prefix != "" {
+			places := trie.FindByPrefixFunc(prefix, cursor+reconcileSuggestPage, nil)
+			for _, place := range places[min(cursor, len(places)):] {
+				suggestions.Result = append(suggestions.Result, reconcileEntity{
+					ID:          place.ID,
+					Name:        place.Name,
+					Description: describePlace(place),
+				})
+			}
+		}
+		c.JSONP(http.StatusOK, suggestions)
+	}
+}
+
+// ReconcileSuggestType suggests the place types in the data
+func ReconcileSuggestType(trie *internal.Trie) gin.HandlerFunc {
+	types := []reconcileEntity{{ID: reconcilePlaceType, Name: "Place"}}
+	seen := map[string]bool{}
+	for _, place := range trie.Places() {
+		if place.Type != "" && !seen[place.Type] {
+			seen[place.Type] = true
+			types = append(types, reconcileEntity{ID: place.Type, Name: placeTypeName(place.Type)})
+		}
+	}
+	slices.SortFunc(types[1:], func(a, b reconcileEntity) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return func(c *gin.Context) {
+		reconcileSuggest(c, types)
+	}
+}
+
+// ReconcileSuggestProperty suggests the properties which narrow down
+// candidates
+func ReconcileSuggestProperty(c *gin.Context) {
+	reconcileSuggest(c, reconcileProperties)
+}
+
+// reconcileSuggest suggests the entities whose name or id contains the
+// prefix
+func reconcileSuggest(c *gin.Context, entities []reconcileEntity) {
+	prefix := strings.ToLower(strings.TrimSpace(c.Query("prefix")))
+	suggestions := reconcileSuggestions{Result: []reconcileEntity{}}
+	for _, entity := range entities {
+		if strings.Contains(strings.ToLower(entity.Name), prefix) || strings.Contains(entity.ID, prefix) {
+			suggestions.Result = append(suggestions.Result, entity)
+		}
+	}
+	c.JSONP(http.StatusOK, suggestions)
+}
@@ -0,0 +1,86 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const reconcileManifestShape = `{
+	"versions": ["0.2"],
+	"name": "Place Names",
+	"identifierSpace": "http://example.com/v1/place-names/",
+	"schemaSpace": "http://example.com/openapi.json#/components/schemas/",
+	"defaultTypes": [{"id": "place", "name": "Place"}],
+	"view": {"url": "http://example.com/v1/place-names/{{id}}"},
+	"preview": {"url": "http://example.com/reconcile/preview?id={{id}}", "width": 400, "height": 90},
+	"suggest": {
+		"entity": {"service_url": "http://example.com", "service_path": "/reconcile/suggest/entity"},
+		"type": {"service_url": "http://example.com", "service_path": "/reconcile/suggest/type"},
+		"property": {"service_url": "http://example.com", "service_path": "/reconcile/suggest/property"}
+	}
+}`
+
+func TestReconcile(t *testing.T) {
+	r := newTestRouter(t)
+
+	t.Run("manifest", func(t *testing.T) {
+		w := serve(t, r, http.MethodGet, "/reconcile", nil, "Host", "places.test")
+		manifest := decodeJSON(t, w, http.StatusOK)
+		checkShape(t, "manifest", manifest, reconcileManifestShape)
+		if manifest["identifierSpace"] != "http://places.test/v1/place-names/" {
+			t.Errorf("expected the identifier space on the request's host, got %v", manifest["identifierSpace"])
+		}
+	})
+
+	t.Run("queries", func(t *testing.T) {
+		form := url.Values{"queries": {`{"q0": {"query": "Newport"}, "q1": {"query": "Oxford", "limit": 1}}`}}
+		w := serve(t, r, http.MethodPost, "/reconcile", strings.NewReader(form.Encode()),
+			"Content-Type", "application/x-www-form-urlencoded")
+		results := decodeJSON(t, w, http.StatusOK)
+		const resultShape = `{"result": [{
+			"id": "oxford", "name": "Oxford", "description": "City in Oxfordshire, England",
+			"type": [{"id": "city", "name": "City"}], "score": 99, "match": true
+		}]}`
+		checkShape(t, "results", results, `{"q0": `+resultShape+`, "q1": `+resultShape+`}`)
+
+		newports := results["q0"].(map[string]any)["result"].([]any)
+		if len(newports) != 2 || newports[0].(map[string]any)["match"] != false {
+			t.Errorf("expected both Newports, neither safe to match, got %v", newports)
+		}
+		oxford := results["q1"].(map[string]any)["result"].([]any)
+		if len(oxford) != 1 || oxford[0].(map[string]any)["id"] != "oxford" || oxford[0].(map[string]any)["match"] != true {
+			t.Errorf("expected Oxford to match, got %v", oxford)
+		}
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		form := url.Values{"queries": {`{"q0": {"query": "` + strings.Repeat("a", maxBodyBytes) + `"}}`}}
+		w := serve(t, r, http.MethodPost, "/reconcile", strings.NewReader(form.Encode()),
+			"Content-Type", "application/x-www-form-urlencoded")
+		if problem := decodeJSON(t, w, http.StatusRequestEntityTooLarge); problem["code"] != "body_too_large" {
+			t.Errorf("expected body_too_large, got %v", problem)
+		}
+	})
+
+	t.Run("jsonp", func(t *testing.T) {
+		w := serve(t, r, http.MethodGet, "/reconcile?callback=handle", nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body)
+		}
+		if contentType := w.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "application/javascript") {
+			t.Errorf("expected JavaScript, got %s", contentType)
+		}
+		body := w.Body.String()
+		if !strings.HasPrefix(body, "handle(") || !strings.HasSuffix(body, ");") {
+			t.Fatalf("expected the manifest wrapped in the callback, got %s", body)
+		}
+		var manifest any
+		if err := json.Unmarshal([]byte(strings.TrimSuffix(strings.TrimPrefix(body, "handle("), ");")), &manifest); err != nil {
+			t.Fatalf("expected the callback's argument to be JSON, got %v: %s", err, body)
+		}
+		checkShape(t, "manifest", manifest, reconcileManifestShape)
+	})
+}
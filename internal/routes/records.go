@@ -0,0 +1,229 @@
+package routes
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin/codec/json"
+	"github.com/map-services/placenames-api/internal"
+	"github.com/map-services/placenames-api/pkg/api"
+	pb "github.com/map-services/placenames-api/pkg/pb/placenamesv1"
+	"google.golang.org/protobuf/proto"
+)
+
+// Record is the flat form of a place used by the CSV, NDJSON and protobuf
+// formats: the place's attributes and location, plus whatever the response
+// added (as with GeoJSON features).
+type Record struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	Relevancy      float64  `json:"relevancy"`
+	Type           string   `json:"type,omitempty"`
+	Country        string   `json:"country,omitempty"`
+	Region         string   `json:"region,omitempty"`
+	County         string   `json:"county,omitempty"`
+	LocalAuthority string   `json:"lad,omitempty"`
+	NationalPark   string   `json:"npark,omitempty"`
+	Lat            *float64 `json:"lat,omitempty"`
+	Long           *float64 `json:"long,omitempty"`
+	Easting        *float64 `json:"easting,omitempty"`
+	Northing       *float64 `json:"northing,omitempty"`
+	GridRef        string   `json:"grid_ref,omitempty"`
+	Geohash        string   `json:"geohash,omitempty"`
+	DistanceKm     *float64 `json:"distance_km,omitempty"`
+}
+
+var csvHeader = []string{
+	"id", "name", "relevancy", "type", "country", "region", "county", "lad", "npark",
+	"lat", "long", "easting", "northing", "grid_ref", "geohash", "distance_km",
+}
+
+func newRecord(place *internal.Place, name string) Record {
+	record := Record{
+		ID:             place.ID,
+		Name:           name,
+		Relevancy:      place.Relevancy,
+		Type:           place.Type,
+		Country:        place.Country,
+		Region:         place.Region,
+		County:         place.County,
+		LocalAuthority: place.LocalAuthority,
+		NationalPark:   place.NationalPark,
+	}
+	if place.HasLocation() {
+		record.Lat, record.Long = &place.Lat, &place.Long
+	}
+	if place.Easting != 0 || place.Northing != 0 {
+		record.Easting, record.Northing = &place.Easting, &place.Northing
+	}
+	return record
+}
+
+// resultRecord adds the attributes of the result's place which its JSON
+// leaves out
+func resultRecord(trie *internal.Trie, result api.Result) Record {
+	record := Record{ID: result.ID, Name: result.Name, Relevancy: result.Relevancy, Type: result.Type}
+	if place, ok := trie.FindByID(result.ID); ok {
+		record = newRecord(place, result.Name)
+	}
+	record.GridRef = result.GridRef
+	record.Geohash = result.Geohash
+	record.DistanceKm = result.DistanceKm
+	return record
+}
+
+func (detail placeDetail) record() Record {
+	return Record{
+		ID:             detail.ID,
+		Name:           detail.Name,
+		Relevancy:      detail.Relevancy,
+		Type:           detail.Type,
+		Country:        detail.Country,
+		Region:         detail.Region,
+		County:         detail.County,
+		LocalAuthority: detail.LocalAuthority,
+		NationalPark:   detail.NationalPark,
+		Lat:            detail.Lat,
+		Long:           detail.Long,
+		Easting:        detail.Easting,
+		Northing:       detail.Northing,
+		GridRef:        detail.GridRef,
+		Geohash:        detail.Geohash,
+	}
+}
+
+func (resp placeList) records() []Record {
+	records := make([]Record, len(resp.Results))
+	for i, result := range resp.Results {
+		records[i] = resultRecord(resp.trie, result)
+	}
+	return records
+}
+
+func (detail placeDetail) records() []Record {
+	return []Record{detail.record()}
+}
+
+func (resp lookupList) records() []Record {
+	records := make([]Record, len(resp.Results))
+	for i, detail := range resp.Results {
+		records[i] = placeDetail(detail).record()
+	}
+	return records
+}
+
+func (record Record) csvRow() []string {
+	formatFloat := func(value *float64) string {
+		if value == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*value, 'f', -1, 64)
+	}
+	return []string{
+		record.ID, record.Name, formatFloat(&record.Relevancy), record.Type,
+		record.Country, record.Region, record.County, record.LocalAuthority, record.NationalPark,
+		formatFloat(record.Lat), formatFloat(record.Long), formatFloat(record.Easting), formatFloat(record.Northing),
+		record.GridRef, record.Geohash, formatFloat(record.DistanceKm),
+	}
+}
+
+// csvRender writes the records as CSV, with a header row
+type csvRender struct {
+	records []Record
+}
+
+func (r csvRender) WriteContentType(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", MIMECSV+"; charset=utf-8")
+}
+
+func (r csvRender) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	writer := csv.NewWriter(w)
+	_ = writer.Write(csvHeader)
+	for _, record := range r.records {
+		_ = writer.Write(record.csvRow())
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ndjsonRender writes each record as a line of JSON
+type ndjsonRender struct {
+	records []Record
+}
+
+func (r ndjsonRender) WriteContentType(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", MIMENDJSON)
+}
+
+func (r ndjsonRender) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	encoder := json.API.NewEncoder(w)
+	for _, record := range r.records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (record Record) protobuf() *pb.Place {
+	return &pb.Place{
+		Id:             record.ID,
+		Name:           record.Name,
+		Relevancy:      record.Relevancy,
+		Type:           record.Type,
+		Lat:            record.Lat,
+		Long:           record.Long,
+		Country:        record.Country,
+		Region:         record.Region,
+		County:         record.County,
+		LocalAuthority: record.LocalAuthority,
+		NationalPark:   record.NationalPark,
+		Easting:        record.Easting,
+		Northing:       record.Northing,
+		GridRef:        record.GridRef,
+		Geohash:        record.Geohash,
+		DistanceKm:     record.DistanceKm,
+	}
+}
+
+func protobufPlaces(records []Record) []*pb.Place {
+	places := make([]*pb.Place, len(records))
+	for i, record := range records {
+		places[i] = record.protobuf()
+	}
+	return places
+}
+
+func (resp placeList) protobuf() proto.Message {
+	list := &pb.PlaceList{
+		MatchType:  resp.MatchType,
+		Results:    protobufPlaces(resp.records()),
+		NextCursor: resp.NextCursor,
+	}
+	if meta := resp.Meta; meta != nil {
+		list.Meta = &pb.ResponseMeta{
+			Query:          meta.Query,
+			Mode:           meta.Mode,
+			MaxResults:     int32(meta.MaxResults),
+			Offset:         int32(meta.Offset),
+			Truncated:      meta.Truncated,
+			DatasetVersion: meta.DatasetVersion,
+			TookMs:         meta.TookMs,
+		}
+		if meta.Total != nil {
+			list.Meta.Total = new(int32(*meta.Total))
+		}
+	}
+	return list
+}
+
+func (detail placeDetail) protobuf() proto.Message {
+	return detail.record().protobuf()
+}
+
+func (resp lookupList) protobuf() proto.Message {
+	return &pb.PlaceList{Results: protobufPlaces(resp.records()), NotFound: resp.NotFound}
+}
@@ -0,0 +1,203 @@
+package routes
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/codec/json"
+	"github.com/gin-gonic/gin/render"
+	"github.com/map-services/placenames-api/internal"
+	"github.com/map-services/placenames-api/pkg/api"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	FormatJSON     = "json"
+	FormatGeoJSON  = "geojson"
+	FormatCSV      = "csv"
+	FormatNDJSON   = "ndjson"
+	FormatMsgPack  = "msgpack"
+	FormatProtobuf = "protobuf"
+
+	MIMEGeoJSON  = "application/geo+json"
+	MIMECSV      = "text/csv"
+	MIMENDJSON   = "application/x-ndjson"
+	MIMEMsgPack  = "application/msgpack"
+	MIMEProtobuf = "application/x-protobuf"
+
+	// HeaderNextCursor carries the next page's cursor for the formats
+	// without anywhere else to put it
+	HeaderNextCursor = "X-Next-Cursor"
+)
+
+var formats = []string{FormatJSON, FormatGeoJSON, FormatCSV, FormatNDJSON, FormatMsgPack, FormatProtobuf}
+
+// formatMIMETypes are the media types accepted for each format, in the
+// order offered to the Accept header
+var formatMIMETypes = []struct {
+	mimeType, format string
+}{
+	{gin.MIMEJSON, FormatJSON},
+	{MIMEGeoJSON, FormatGeoJSON},
+	{MIMECSV, FormatCSV},
+	{MIMENDJSON, FormatNDJSON},
+	{MIMEMsgPack, FormatMsgPack},
+	{binding.MIMEMSGPACK, FormatMsgPack},
+	{MIMEProtobuf, FormatProtobuf},
+}
+
+type Feature struct {
+	Type       string         `json:"type"`
+	Geometry   *Point         `json:"geometry"` // null when the place has no location
+	Properties map[string]any `json:"properties"`
+}
+
+type Point struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"` // [long, lat], as per RFC 7946
+}
+
+type FeatureCollection struct {
+	Type       string            `json:"type"`
+	MatchType  string            `json:"match_type,omitempty"`
+	Features   []Feature         `json:"features"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+	Meta       *api.ResponseMeta `json:"meta,omitempty"`
+}
+
+// negotiateFormat picks the response format from ?format=, falling back to
+// the Accept header, and then to JSON.
+func negotiateFormat(c *gin.Context) (string, error) {
+	if format := c.Query("format"); format != "" {
+		if !slices.Contains(formats, format) {
+			return "", paramErrorf("format", "format must be one of: %s", strings.Join(formats, ", "))
+		}
+		return format, nil
+	}
+
+	offered := make([]string, len(formatMIMETypes))
+	for i, m := range formatMIMETypes {
+		offered[i] = m.mimeType
+	}
+	negotiated := c.NegotiateFormat(offered...)
+	for _, m := range formatMIMETypes {
+		if m.mimeType == negotiated {
+			return m.format, nil
+		}
+	}
+	return FormatJSON, nil
+}
+
+// placeRenderer is implemented by every response type containing places, so
+// that they can be rendered in any format.
+type placeRenderer interface {
+	body() any // the response as rendered in JSON and MessagePack
+	geoJSON() any
+	records() []Record
+	protobuf() proto.Message
+}
+
+// placeList renders a search's results. They only carry some of each
+// place's attributes, so the rest are looked up in the trie.
+type placeList struct {
+	api.PlaceResponse
+	trie *internal.Trie
+}
+
+// placeDetail renders a place's full record
+type placeDetail api.PlaceDetail
+
+// lookupList renders the places found by a lookup
+type lookupList api.LookupResponse
+
+// respond renders a place response in the negotiated format; every
+// endpoint returning places should go through here.
+func respond(c *gin.Context, resp placeRenderer) {
+	c.Header("Vary", "Accept")
+
+	format, err := negotiateFormat(c)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	if places, ok := resp.(placeList); ok && places.NextCursor != "" && (format == FormatCSV || format == FormatNDJSON) {
+		c.Header(HeaderNextCursor, places.NextCursor)
+	}
+
+	switch format {
+	case FormatGeoJSON:
+		body, err := json.API.Marshal(resp.geoJSON())
+		if err != nil {
+			_ = c.Error(err)
+			abortWithStatus(c, http.StatusInternalServerError, api.CodeInternalError, "failed to render GeoJSON")
+			return
+		}
+		c.Data(http.StatusOK, MIMEGeoJSON, body)
+	case FormatCSV:
+		c.Render(http.StatusOK, csvRender{records: resp.records()})
+	case FormatNDJSON:
+		c.Render(http.StatusOK, ndjsonRender{records: resp.records()})
+	case FormatMsgPack:
+		c.Render(http.StatusOK, render.MsgPack{Data: resp.body()})
+	case FormatProtobuf:
+		c.ProtoBuf(http.StatusOK, resp.protobuf())
+	default:
+		c.JSON(http.StatusOK, resp.body())
+	}
+}
+
+func (resp placeList) body() any {
+	return resp.PlaceResponse
+}
+
+func (resp placeList) geoJSON() any {
+	features := make([]Feature, len(resp.Results))
+	for i, record := range resp.records() {
+		props := map[string]any{
+			"id":        record.ID,
+			"name":      record.Name,
+			"relevancy": record.Relevancy,
+		}
+		if record.DistanceKm != nil {
+			props["distance_km"] = *record.DistanceKm
+		}
+		if record.GridRef != "" {
+			props["grid_ref"] = record.GridRef
+		}
+		if record.Geohash != "" {
+			props["geohash"] = record.Geohash
+		}
+		features[i] = recordFeature(record, props)
+	}
+	return FeatureCollection{
+		Type:       "FeatureCollection",
+		MatchType:  resp.MatchType,
+		Features:   features,
+		NextCursor: resp.NextCursor,
+		Meta:       resp.Meta,
+	}
+}
+
+// recordFeature adds the place's attributes & location to the properties
+func recordFeature(record Record, props map[string]any) Feature {
+	feature := Feature{Type: "Feature", Properties: props}
+	for key, value := range map[string]string{
+		"type":    record.Type,
+		"country": record.Country,
+		"region":  record.Region,
+		"county":  record.County,
+		"lad":     record.LocalAuthority,
+		"npark":   record.NationalPark,
+	} {
+		if value != "" {
+			props[key] = value
+		}
+	}
+	if record.Lat != nil && record.Long != nil {
+		feature.Geometry = &Point{Type: "Point", Coordinates: []float64{*record.Long, *record.Lat}}
+	}
+	return feature
+}
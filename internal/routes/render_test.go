@@ -0,0 +1,156 @@
+package routes
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/map-services/placenames-api/pkg/api"
+	pb "github.com/map-services/placenames-api/pkg/pb/placenamesv1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	r := newTestRouter(t)
+	const target = "/v1/place-names?q=l"
+
+	for name, test := range map[string]struct {
+		query, accept, want string
+	}{
+		"default":            {"", "", "application/json"},
+		"anything":           {"", "*/*", "application/json"},
+		"accept":             {"", MIMECSV, MIMECSV},
+		"accept order":       {"", "application/x-ndjson, text/csv", MIMENDJSON},
+		"accept msgpack":     {"", "application/x-msgpack", MIMEMsgPack},
+		"accept unoffered":   {"", "text/html", "application/json"},
+		"format":             {"&format=ndjson", "", MIMENDJSON},
+		"format over accept": {"&format=protobuf", MIMECSV, MIMEProtobuf},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := serve(t, r, http.MethodGet, target+test.query, nil, "Accept", test.accept)
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body)
+			}
+			if contentType := w.Header().Get("Content-Type"); !strings.HasPrefix(contentType, test.want) {
+				t.Errorf("expected %s, got %s", test.want, contentType)
+			}
+			if vary := w.Header().Get("Vary"); vary != "Accept" {
+				t.Errorf("expected the response to vary by Accept, got %q", vary)
+			}
+		})
+	}
+
+	t.Run("unknown format", func(t *testing.T) {
+		problem := decodeJSON(t, serve(t, r, http.MethodGet, target+"&format=xml", nil), http.StatusBadRequest)
+		if problem["code"] != "invalid_parameter" {
+			t.Errorf("expected invalid_parameter, got %v", problem)
+		}
+	})
+}
+
+func TestRespondFormats(t *testing.T) {
+	r := newTestRouter(t)
+	const target = "/v1/place-names?q=l&format="
+
+	want := resultIDs(t, decodeJSON(t, serve(t, r, http.MethodGet, target+FormatJSON, nil), http.StatusOK))
+	if len(want) < 2 {
+		t.Fatalf("expected several places to compare, got %v", want)
+	}
+
+	for format, decode := range map[string]func(t *testing.T, body []byte) []string{
+		FormatCSV: func(t *testing.T, body []byte) []string {
+			rows, err := csv.NewReader(strings.NewReader(string(body))).ReadAll()
+			if err != nil || len(rows) == 0 || !slices.Equal(rows[0], csvHeader) {
+				t.Fatalf("expected a header row, got %v: %s", err, body)
+			}
+			var ids []string
+			for _, row := range rows[1:] {
+				ids = append(ids, row[0])
+			}
+			return ids
+		},
+		FormatNDJSON: func(t *testing.T, body []byte) []string {
+			var ids []string
+			lines := bufio.NewScanner(strings.NewReader(string(body)))
+			for lines.Scan() {
+				var record Record
+				if err := json.Unmarshal(lines.Bytes(), &record); err != nil {
+					t.Fatalf("expected a record per line, got %v: %s", err, lines.Text())
+				}
+				ids = append(ids, record.ID)
+			}
+			return ids
+		},
+		FormatMsgPack: func(t *testing.T, body []byte) []string {
+			var resp api.PlaceResponse
+			if err := binding.MsgPack.BindBody(body, &resp); err != nil {
+				t.Fatalf("expected MessagePack, got %v", err)
+			}
+			if resp.MatchType != api.MatchTypePrefix {
+				t.Errorf("expected a prefix match, got %q", resp.MatchType)
+			}
+			var ids []string
+			for _, result := range resp.Results {
+				ids = append(ids, result.ID)
+			}
+			return ids
+		},
+		FormatProtobuf: func(t *testing.T, body []byte) []string {
+			var list pb.PlaceList
+			if err := proto.Unmarshal(body, &list); err != nil {
+				t.Fatalf("expected a PlaceList, got %v", err)
+			}
+			var ids []string
+			for _, place := range list.GetResults() {
+				ids = append(ids, place.GetId())
+			}
+			return ids
+		},
+	} {
+		t.Run(format, func(t *testing.T) {
+			w := serve(t, r, http.MethodGet, target+format, nil)
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body)
+			}
+			if ids := decode(t, w.Body.Bytes()); !slices.Equal(ids, want) {
+				t.Errorf("expected %v, as in JSON, got %v", want, ids)
+			}
+		})
+	}
+}
+
+func TestGeoJSON(t *testing.T) {
+	r := newTestRouter(t)
+
+	w := serve(t, r, http.MethodGet, "/v1/place-names?q=l", nil, "Accept", MIMEGeoJSON)
+	if contentType := w.Header().Get("Content-Type"); contentType != MIMEGeoJSON {
+		t.Errorf("expected %s, got %s", MIMEGeoJSON, contentType)
+	}
+	collection := decodeJSON(t, w, http.StatusOK)
+	if collection["type"] != "FeatureCollection" {
+		t.Errorf("expected a FeatureCollection, got %v", collection["type"])
+	}
+
+	geometries := map[string]any{}
+	for _, feature := range collection["features"].([]any) {
+		feature := feature.(map[string]any)
+		geometry, ok := feature["geometry"]
+		if feature["type"] != "Feature" || !ok {
+			t.Errorf("expected a Feature with a geometry, got %v", feature)
+		}
+		geometries[feature["properties"].(map[string]any)["id"].(string)] = geometry
+	}
+
+	london, _ := geometries["london"].(map[string]any)
+	if coordinates, _ := london["coordinates"].([]any); london["type"] != "Point" || !slices.Equal(coordinates, []any{-0.1276, 51.5072}) {
+		t.Errorf("expected London as a [long, lat] point, got %v", geometries["london"])
+	}
+	if luton, ok := geometries["luton"]; !ok || luton != nil {
+		t.Errorf("expected Luton, without a location, to have a null geometry, got %v", luton)
+	}
+}
@@ -0,0 +1,83 @@
+package routes
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/map-services/placenames-api/internal"
+	cachecontrol "go.eigsys.de/gin-cachecontrol/v2"
+)
+
+// Register adds the API routes (and the OpenAPI document describing them)
+// to the engine. Server concerns such as logging, metrics and health checks
+// are left to the caller. publicURL is the scheme and host clients reach
+// the API on, for the documents needing absolute URLs; when empty, these
+// are built from each request, and not cached.
+func Register(r *gin.Engine, trie *internal.Trie, spatial *internal.SpatialIndex, publicURL string) error {
+	if publicURL != "" {
+		u, err := url.Parse(publicURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid public URL %q: must be an absolute http or https URL", publicURL)
+		}
+		publicURL = strings.TrimSuffix(publicURL, "/")
+	}
+
+	doc, err := LoadOpenAPI()
+	if err != nil {
+		return err
+	}
+	validateRequests, err := ValidateRequests(doc)
+	if err != nil {
+		return err
+	}
+
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(NoRoute)
+	r.NoMethod(NoMethod)
+
+	r.GET("/openapi.json", OpenAPISpec)
+	r.GET("/docs", SwaggerUI)
+	docs := r.Group("/docs/assets", cachecontrol.New(cachecontrol.Config{
+		MaxAge: cachecontrol.Duration(24 * time.Hour),
+		Public: true,
+	}))
+	docs.StaticFS("/", SwaggerUIAssets())
+	r.GET("/opensearch.xml", OpenSearchDescription(publicURL))
+
+	matcher := internal.NewMatcher(trie)
+
+	v1 := r.Group("/v1")
+	v1.Use(cachecontrol.New(cachecontrol.Config{
+		MaxAge:    cachecontrol.Duration(28 * 24 * time.Hour),
+		Immutable: true,
+		Public:    true,
+	}), validateRequests)
+	v1.GET("/place-names", PlaceNames(trie, spatial))
+	v1.GET("/place-names/prefix/:query", Prefix(trie, spatial))
+	v1.GET("/place-names/distance", Distance(trie))
+	v1.GET("/place-names/stream", Stream(trie, spatial))
+	v1.GET("/place-names/:id", PlaceByID(trie))
+	v1.GET("/place-names/:id/nearby", Nearby(trie, spatial))
+	v1.POST("/place-names/lookup", Lookup(trie))
+	v1.POST("/place-names/search", Search(trie, spatial))
+	v1.POST("/place-names/match", Match(trie, matcher))
+	v1.GET("/suggest", Suggest(trie, publicURL))
+	v1.GET("/tiles/:z/:x/:y", Tiles(spatial))
+
+	// outside /v1 (and the spec): the shapes are Elasticsearch's, Pelias's
+	// and the Reconciliation API's, not ours
+	es := r.Group("/es", ElasticProduct)
+	es.POST("/:index/_search", ElasticsearchSearch(trie))
+	r.GET("/pelias/v1/autocomplete", PeliasAutocomplete(trie, spatial))
+
+	r.GET("/reconcile", Reconcile(trie, matcher, publicURL))
+	r.POST("/reconcile", Reconcile(trie, matcher, publicURL))
+	r.GET("/reconcile/preview", ReconcilePreview(trie))
+	r.GET("/reconcile/suggest/entity", ReconcileSuggestEntity(trie))
+	r.GET("/reconcile/suggest/type", ReconcileSuggestType(trie))
+	r.GET("/reconcile/suggest/property", ReconcileSuggestProperty)
+	return nil
+}
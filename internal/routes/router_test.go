@@ -0,0 +1,139 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/map-services/placenames-api/internal"
+)
+
+// newTestRouter registers the routes over a handful of places
+func newTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	trie := internal.NewTrie(10)
+	for _, place := range []*internal.Place{
+		{ID: "london", Name: "London", Relevancy: 1.0, Lat: 51.5072, Long: -0.1276, Type: internal.PlaceTypeCity, Country: "England", Region: "London"},
+		{ID: "croydon", Name: "Croydon", Relevancy: 0.7, Lat: 51.3762, Long: -0.0982, Type: internal.PlaceTypeTown, Country: "England", Region: "London"},
+		{ID: "oxford", Name: "Oxford", Relevancy: 0.9, Lat: 51.752, Long: -1.2577, Type: internal.PlaceTypeCity, Country: "England", County: "Oxfordshire"},
+		{ID: "luton", Name: "Luton", Relevancy: 0.6, Type: internal.PlaceTypeTown, Country: "England"},
+		{ID: "llandudno", Name: "Llandudno", Relevancy: 0.5, Lat: 53.3241, Long: -3.8276, Type: internal.PlaceTypeTown, Country: "Wales"},
+		{ID: "newport-wales", Name: "Newport", Relevancy: 0.8, Lat: 51.5842, Long: -2.9977, Type: internal.PlaceTypeCity, Country: "Wales"},
+		{ID: "newport-iow", Name: "Newport", Relevancy: 0.5, Lat: 50.701, Long: -1.2883, Type: internal.PlaceTypeTown, Country: "England"},
+		{ID: "st-albans", Name: "St Albans", Relevancy: 0.7, Lat: 51.7527, Long: -0.3394, Type: internal.PlaceTypeCity, Country: "England"},
+	} {
+		trie.Insert(place)
+	}
+	spatial := internal.NewSpatialIndex(trie.Places(), 0.1)
+
+	r := gin.New()
+	r.Use(RequestID(), gin.CustomRecovery(Recovery))
+	if err := Register(r, trie, spatial, ""); err != nil {
+		t.Fatalf("failed to register routes: %v", err)
+	}
+	return r
+}
+
+// serve sends the request through the router, returning the response
+func serve(t *testing.T, r *gin.Engine, method, target string, body io.Reader, headers ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, body)
+	for i := 0; i+1 < len(headers); i += 2 {
+		if headers[i] == "Host" {
+			req.Host = headers[i+1] // net/http takes it out of the headers
+			continue
+		}
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// decodeJSON decodes the response body, failing the test if it isn't the
+// expected status or isn't JSON
+func decodeJSON(t *testing.T, w *httptest.ResponseRecorder, status int) map[string]any {
+	t.Helper()
+	if w.Code != status {
+		t.Fatalf("expected status %d, got %d: %s", status, w.Code, w.Body)
+	}
+	var body map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("expected a JSON body, got %v: %s", err, w.Body)
+	}
+	return body
+}
+
+// resultIDs returns the ids of a place response's results, in order
+func resultIDs(t *testing.T, body map[string]any) []string {
+	t.Helper()
+	results, ok := body["results"].([]any)
+	if !ok {
+		t.Fatalf("expected results, got %v", body)
+	}
+	ids := make([]string, len(results))
+	for i, result := range results {
+		ids[i], _ = result.(map[string]any)["id"].(string)
+	}
+	return ids
+}
+
+// checkShape compares the structure of a decoded JSON value with the
+// expected shape: objects must have exactly the same keys, every element of
+// an array must match the shape's first, and other values must be of the
+// same JSON type. Values which vary, like timings, are left alone.
+func checkShape(t *testing.T, path string, got any, shape string) {
+	t.Helper()
+	var want any
+	if err := json.Unmarshal([]byte(shape), &want); err != nil {
+		t.Fatalf("invalid shape: %v", err)
+	}
+	for _, mismatch := range shapeMismatches(path, got, want) {
+		t.Error(mismatch)
+	}
+}
+
+func shapeMismatches(path string, got, want any) []string {
+	switch want := want.(type) {
+	case map[string]any:
+		got, ok := got.(map[string]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected an object, got %v", path, got)}
+		}
+		var mismatches []string
+		for key := range got {
+			if _, ok := want[key]; !ok {
+				mismatches = append(mismatches, fmt.Sprintf("%s: unexpected %q", path, key))
+			}
+		}
+		for key, value := range want {
+			if _, ok := got[key]; !ok {
+				mismatches = append(mismatches, fmt.Sprintf("%s: missing %q", path, key))
+				continue
+			}
+			mismatches = append(mismatches, shapeMismatches(path+"."+key, got[key], value)...)
+		}
+		return mismatches
+	case []any:
+		got, ok := got.([]any)
+		if !ok || (len(want) == 0) != (len(got) == 0) {
+			return []string{fmt.Sprintf("%s: expected an array like %v, got %v", path, want, got)}
+		}
+		var mismatches []string
+		for i, element := range got {
+			mismatches = append(mismatches, shapeMismatches(fmt.Sprintf("%s[%d]", path, i), element, want[0])...)
+		}
+		return mismatches
+	default:
+		if reflect.TypeOf(got) != reflect.TypeOf(want) {
+			return []string{fmt.Sprintf("%s: expected a value like %v, got %v", path, want, got)}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,115 @@
+package routes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/codec/json"
+	"github.com/map-services/placenames-api/internal"
+	"github.com/map-services/placenames-api/pkg/api"
+)
+
+func toPositions(coords [][]float64) ([]internal.Position, error) {
+	ring := make([]internal.Position, len(coords))
+	for i, coord := range coords {
+		if len(coord) < 2 {
+			return nil, fmt.Errorf("positions must have a longitude and latitude")
+		}
+		ring[i] = internal.Position{coord[0], coord[1]}
+	}
+	return ring, nil
+}
+
+func toPolygon(coords [][][]float64) (internal.Polygon, error) {
+	polygon := make(internal.Polygon, len(coords))
+	for i, ring := range coords {
+		positions, err := toPositions(ring)
+		if err != nil {
+			return nil, err
+		}
+		polygon[i] = positions
+	}
+	return polygon, nil
+}
+
+// parseShape converts a GeoJSON Polygon or MultiPolygon geometry
+func parseShape(geometry api.Geometry) (internal.MultiPolygon, error) {
+	var shape internal.MultiPolygon
+	switch geometry.Type {
+	case "Polygon":
+		var coords [][][]float64
+		if err := json.API.Unmarshal(geometry.Coordinates, &coords); err != nil {
+			return nil, fmt.Errorf("invalid Polygon coordinates: %w", err)
+		}
+		polygon, err := toPolygon(coords)
+		if err != nil {
+			return nil, err
+		}
+		shape = internal.MultiPolygon{polygon}
+
+	case "MultiPolygon":
+		var coords [][][][]float64
+		if err := json.API.Unmarshal(geometry.Coordinates, &coords); err != nil {
+			return nil, fmt.Errorf("invalid MultiPolygon coordinates: %w", err)
+		}
+		for _, polygonCoords := range coords {
+			polygon, err := toPolygon(polygonCoords)
+			if err != nil {
+				return nil, err
+			}
+			shape = append(shape, polygon)
+		}
+
+	default:
+		return nil, fmt.Errorf("geometry type must be Polygon or MultiPolygon, got %q", geometry.Type)
+	}
+
+	return shape, shape.Validate()
+}
+
+func Search(trie *internal.Trie, spatial *internal.SpatialIndex) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req api.SearchRequest
+		limitBody(c)
+		if err := c.ShouldBindJSON(&req); err != nil {
+			invalidBody(c, err)
+			return
+		}
+
+		if req.MaxResults == 0 {
+			req.MaxResults = defaultMaxResults
+		}
+		if req.MaxResults < 0 || req.MaxResults > trie.TopK() {
+			badRequest(c, paramErrorf("max_results", "max_results must be a positive integer less than or equal to %d", trie.TopK()))
+			return
+		}
+
+		shape, err := parseShape(req.Geometry)
+		if err != nil {
+			badRequest(c, paramErrorf("geometry", "%v", err))
+			return
+		}
+		fields, err := parseFields(c.Query("fields"))
+		if err != nil {
+			badRequest(c, err)
+			return
+		}
+
+		prefix := strings.ToLower(req.Prefix)
+		matches := spatial.WithinShape(shape)
+		filtered := matches[:0]
+		for _, place := range matches {
+			if strings.HasPrefix(strings.ToLower(place.Name), prefix) {
+				filtered = append(filtered, place)
+			}
+		}
+		internal.SortByRelevancy(filtered)
+
+		results := make([]api.Result, min(req.MaxResults, len(filtered)))
+		for i, place := range filtered[:len(results)] {
+			results[i] = newResult(place, applyPrefixCasing(place.Name, req.Prefix), fields)
+		}
+		respond(c, placeList{api.PlaceResponse{MatchType: api.MatchTypePolygon, Results: results}, trie})
+	}
+}
@@ -0,0 +1,69 @@
+package routes
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// around London, Croydon and St Albans, but not Oxford or Luton, which has
+// no location
+const searchLondon = `{"type": "Polygon", "coordinates": [[[-0.6, 51.2], [0.3, 51.2], [0.3, 51.9], [-0.6, 51.9], [-0.6, 51.2]]]}`
+
+func TestSearch(t *testing.T) {
+	r := newTestRouter(t)
+
+	for name, test := range map[string]struct {
+		body string
+		want []string
+	}{
+		"polygon":     {`{"geometry": ` + searchLondon + `}`, []string{"london", "croydon", "st-albans"}},
+		"prefix":      {`{"prefix": "st", "geometry": ` + searchLondon + `}`, []string{"st-albans"}},
+		"max results": {`{"max_results": 1, "geometry": ` + searchLondon + `}`, []string{"london"}},
+		"multipolygon": {`{"geometry": {"type": "MultiPolygon", "coordinates": [
+			[[[-0.2, 51.4], [-0.1, 51.4], [-0.1, 51.6], [-0.2, 51.6], [-0.2, 51.4]]],
+			[[[-1.3, 51.7], [-1.2, 51.7], [-1.2, 51.8], [-1.3, 51.8], [-1.3, 51.7]]]
+		]}}`, []string{"london", "oxford"}},
+		"nothing inside": {`{"geometry": {"type": "Polygon", "coordinates": [[[10, 10], [11, 10], [11, 11], [10, 10]]]}}`, []string{}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := serve(t, r, http.MethodPost, "/v1/place-names/search", strings.NewReader(test.body), "Content-Type", "application/json")
+			body := decodeJSON(t, w, http.StatusOK)
+			if body["match_type"] != "polygon" {
+				t.Errorf("expected a polygon match, got %v", body["match_type"])
+			}
+			if ids := resultIDs(t, body); !slices.Equal(ids, test.want) {
+				t.Errorf("expected %v, got %v", test.want, ids)
+			}
+		})
+	}
+
+	t.Run("prefix casing", func(t *testing.T) {
+		w := serve(t, r, http.MethodPost, "/v1/place-names/search", strings.NewReader(`{"prefix": "ST", "geometry": `+searchLondon+`}`),
+			"Content-Type", "application/json")
+		results := decodeJSON(t, w, http.StatusOK)["results"].([]any)
+		if len(results) != 1 || !strings.HasPrefix(results[0].(map[string]any)["name"].(string), "ST") {
+			t.Errorf("expected the name to keep the prefix's casing, got %v", results)
+		}
+	})
+
+	for name, test := range map[string]struct {
+		body string
+		code string
+	}{
+		"not json":         {`{"geometry":`, "invalid_body"},
+		"no geometry":      {`{"prefix": "lon"}`, "invalid_body"},
+		"point":            {`{"geometry": {"type": "Point", "coordinates": [0, 51]}}`, "invalid_body"},
+		"unclosed ring":    {`{"geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1]]]}}`, "invalid_parameter"},
+		"too many results": {`{"max_results": 1000, "geometry": ` + searchLondon + `}`, "invalid_parameter"},
+		"negative results": {`{"max_results": -1, "geometry": ` + searchLondon + `}`, "invalid_body"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := serve(t, r, http.MethodPost, "/v1/place-names/search", strings.NewReader(test.body), "Content-Type", "application/json")
+			if problem := decodeJSON(t, w, http.StatusBadRequest); problem["code"] != test.code {
+				t.Errorf("expected %s, got %v", test.code, problem)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/map-services/placenames-api/internal"
+	"github.com/map-services/placenames-api/pkg/api"
+)
+
+// StreamRequest is a keystroke sent over /v1/place-names/stream
+type StreamRequest struct {
+	Seq   uint64 `json:"seq"`
+	Query string `json:"q"`
+}
+
+// StreamResponse answers a keystroke, with either the results or a problem.
+// Keystrokes superseded before their search finished are never answered.
+type StreamResponse struct {
+	Seq uint64 `json:"seq"`
+	*api.PlaceResponse
+	Error *api.Problem `json:"error,omitempty"`
+}
+
+const (
+	maxStreamMessage = 1024
+	streamWriteWait  = 10 * time.Second
+	streamPongWait   = 60 * time.Second
+	streamPingPeriod = streamPongWait * 9 / 10
+)
+
+var upgrader = websocket.Upgrader{
+	// the API is public and read-only, as per the CORS policy
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+// Stream is a search-as-you-type session over a WebSocket, saving a
+// request per keystroke. Every option other than q is fixed for the
+// session by the query parameters used to connect; each message then
+// carries the query so far.
+func Stream(trie *internal.Trie, spatial *internal.SpatialIndex) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		opts, err := parseSearchOptions(c, "", trie.TopK())
+		if err != nil {
+			badRequest(c, err)
+			return
+		}
+		if !websocket.IsWebSocketUpgrade(c.Request) {
+			abortWithStatus(c, http.StatusUpgradeRequired, api.CodeUpgradeRequired, "this endpoint requires a WebSocket connection")
+			return
+		}
+
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			return // the upgrader has already replied
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		session := internal.NewTypeahead[[]byte](c.Request.Context())
+		go readKeystrokes(conn, session)
+
+		ping := time.NewTicker(streamPingPeriod)
+		defer ping.Stop()
+		for {
+			select {
+			case keystroke, ok := <-session.Keystrokes():
+				if !ok {
+					return
+				}
+				resp, ok := answerKeystroke(c, trie, spatial, opts, keystroke)
+				if !ok {
+					continue // superseded
+				}
+				_ = conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+				if err := conn.WriteJSON(resp); err != nil {
+					return
+				}
+			case <-ping.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteWait)); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// readKeystrokes pushes each message into the session until the connection
+// is closed, or goes quiet for longer than the ping period.
+func readKeystrokes(conn *websocket.Conn, session *internal.Typeahead[[]byte]) {
+	defer session.Close()
+	conn.SetReadLimit(maxStreamMessage)
+	_ = conn.SetReadDeadline(time.Now().Add(streamPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(streamPongWait))
+	})
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		session.Push(message)
+	}
+}
+
+// answerKeystroke searches for the keystroke's query, reporting false if it
+// was superseded before the search finished.
+func answerKeystroke(c *gin.Context, trie *internal.Trie, spatial *internal.SpatialIndex, opts SearchOptions, keystroke internal.Keystroke[[]byte]) (StreamResponse, bool) {
+	var req StreamRequest
+	if err := json.Unmarshal(keystroke.Request, &req); err != nil {
+		problem := newProblem(c, api.Problem{Status: http.StatusBadRequest, Code: api.CodeInvalidBody, Detail: "invalid message: " + err.Error()})
+		return StreamResponse{Error: &problem}, true
+	}
+	resp := StreamResponse{Seq: req.Seq}
+
+	opts.Query, opts.start = req.Query, time.Now()
+	var err error
+	if utf8.RuneCountInString(req.Query) > maxQueryLength {
+		err = paramErrorf("q", "q must be no more than %d characters", maxQueryLength)
+	} else {
+		var places api.PlaceResponse
+		if places, err = findPlaces(keystroke.Ctx, trie, spatial, opts); err == nil {
+			resp.PlaceResponse = &places
+		}
+	}
+	if errors.Is(err, context.Canceled) {
+		return resp, false
+	}
+	if err != nil {
+		problem := newProblem(c, errorProblem(err))
+		resp.Error = &problem
+	}
+	return resp, true
+}
@@ -0,0 +1,96 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestStream(t *testing.T) {
+	server := httptest.NewServer(newTestRouter(t))
+	t.Cleanup(server.Close)
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/v1/place-names/stream?max_results=2"
+
+	t.Run("keystrokes", func(t *testing.T) {
+		conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+		// each is answered before the next is sent, so neither is superseded
+		for _, keystroke := range []struct {
+			req  StreamRequest
+			want []string
+		}{
+			{StreamRequest{Seq: 1, Query: "n"}, []string{"newport-wales", "newport-iow"}},
+			{StreamRequest{Seq: 2, Query: "ox"}, []string{"oxford"}},
+		} {
+			if err := conn.WriteJSON(keystroke.req); err != nil {
+				t.Fatalf("failed to send %v: %v", keystroke.req, err)
+			}
+			var resp StreamResponse
+			if err := conn.ReadJSON(&resp); err != nil {
+				t.Fatalf("failed to read the answer to %v: %v", keystroke.req, err)
+			}
+			if resp.Seq != keystroke.req.Seq || resp.Error != nil || resp.PlaceResponse == nil {
+				t.Fatalf("expected results for %d, got %+v", keystroke.req.Seq, resp)
+			}
+			var ids []string
+			for _, result := range resp.Results {
+				ids = append(ids, result.ID)
+			}
+			if !slices.Equal(ids, keystroke.want) {
+				t.Errorf("expected %v for %q, got %v", keystroke.want, keystroke.req.Query, ids)
+			}
+		}
+
+		if err := conn.WriteJSON(map[string]any{"seq": "three"}); err != nil {
+			t.Fatalf("failed to send: %v", err)
+		}
+		var resp StreamResponse
+		if err := conn.ReadJSON(&resp); err != nil {
+			t.Fatalf("failed to read the answer to an invalid message: %v", err)
+		}
+		if resp.Error == nil || resp.Error.Code != "invalid_body" || resp.PlaceResponse != nil {
+			t.Errorf("expected an invalid_body problem, got %+v", resp)
+		}
+
+		// the server echoes the close, then hangs up
+		closing := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+		if err := conn.WriteControl(websocket.CloseMessage, closing, time.Now().Add(time.Second)); err != nil {
+			t.Fatalf("failed to close: %v", err)
+		}
+		var closeErr *websocket.CloseError
+		if _, _, err := conn.ReadMessage(); !errors.As(err, &closeErr) || closeErr.Code != websocket.CloseNormalClosure {
+			t.Errorf("expected a normal closure, got %v", err)
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, resp, err := websocket.DefaultDialer.Dial(url+"&mode=telepathy", nil)
+		if err == nil || resp == nil || resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected the connection to be refused, got %v", err)
+		}
+	})
+
+	t.Run("not a websocket", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/v1/place-names/stream")
+		if err != nil {
+			t.Fatalf("failed to get: %v", err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusUpgradeRequired {
+			t.Errorf("expected status 426, got %d", resp.StatusCode)
+		}
+	})
+}
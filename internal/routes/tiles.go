@@ -0,0 +1,51 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/map-services/placenames-api/internal"
+)
+
+const (
+	MIMEVectorTile  = "application/vnd.mapbox-vector-tile"
+	tileLayerName   = "places"
+	maxTileFeatures = 1000
+)
+
+func parseTileCoord(c *gin.Context) (internal.TileCoord, error) {
+	yStr, ok := strings.CutSuffix(c.Param("y"), ".mvt")
+	if !ok {
+		return internal.TileCoord{}, paramErrorf("y", "tile must be requested as {z}/{x}/{y}.mvt")
+	}
+
+	z, err := strconv.Atoi(c.Param("z"))
+	if err != nil || z < 0 || z > internal.MaxTileZoom {
+		return internal.TileCoord{}, paramErrorf("z", "zoom must be an integer from 0 to %d", internal.MaxTileZoom)
+	}
+	x, errX := strconv.Atoi(c.Param("x"))
+	y, errY := strconv.Atoi(yStr)
+	tile := internal.TileCoord{Z: z, X: x, Y: y}
+	switch {
+	case errX != nil || x < 0 || x >= 1<<z:
+		return internal.TileCoord{}, paramErrorf("x", "x must be an integer in range for zoom level %d", z)
+	case errY != nil || !tile.Valid():
+		return internal.TileCoord{}, paramErrorf("y", "y must be an integer in range for zoom level %d", z)
+	}
+	return tile, nil
+}
+
+func Tiles(spatial *internal.SpatialIndex) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tile, err := parseTileCoord(c)
+		if err != nil {
+			badRequest(c, err)
+			return
+		}
+
+		places := spatial.Within(tile.Bounds())
+		c.Data(http.StatusOK, MIMEVectorTile, internal.EncodeTile(tileLayerName, tile, places, maxTileFeatures))
+	}
+}
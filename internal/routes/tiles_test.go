@@ -0,0 +1,53 @@
+package routes
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+)
+
+func TestTiles(t *testing.T) {
+	r := newTestRouter(t)
+
+	t.Run("tile", func(t *testing.T) {
+		w := serve(t, r, http.MethodGet, "/v1/tiles/2/1/1.mvt", nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body)
+		}
+		if contentType := w.Header().Get("Content-Type"); contentType != MIMEVectorTile {
+			t.Errorf("expected %s, got %s", MIMEVectorTile, contentType)
+		}
+		// only London is relevant enough to show at zoom level 2
+		body := w.Body.Bytes()
+		if !bytes.Contains(body, []byte(tileLayerName)) || !bytes.Contains(body, []byte("london")) || bytes.Contains(body, []byte("oxford")) {
+			t.Errorf("expected a places layer with just London, got %q", body)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		w := serve(t, r, http.MethodGet, "/v1/tiles/2/3/3.mvt", nil)
+		if w.Code != http.StatusOK || w.Body.Len() != 0 {
+			t.Errorf("expected an empty tile, got %d: %q", w.Code, w.Body)
+		}
+		if contentType := w.Header().Get("Content-Type"); contentType != MIMEVectorTile {
+			t.Errorf("expected %s, got %s", MIMEVectorTile, contentType)
+		}
+	})
+
+	for name, target := range map[string]string{
+		"no suffix":      "/v1/tiles/2/1/1",
+		"wrong suffix":   "/v1/tiles/2/1/1.png",
+		"zoom too deep":  "/v1/tiles/19/0/0.mvt",
+		"negative zoom":  "/v1/tiles/-1/0/0.mvt",
+		"x out of range": "/v1/tiles/2/4/1.mvt",
+		"y out of range": "/v1/tiles/2/1/4.mvt",
+		"not a number":   "/v1/tiles/2/one/1.mvt",
+	} {
+		t.Run(name, func(t *testing.T) {
+			problem := decodeJSON(t, serve(t, r, http.MethodGet, target, nil), http.StatusBadRequest)
+			if problem["code"] != "invalid_parameter" {
+				t.Errorf("expected invalid_parameter, got %v", problem)
+			}
+		})
+	}
+}
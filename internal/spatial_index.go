@@ -0,0 +1,191 @@
+package internal
+
+import (
+	"math"
+	"sort"
+)
+
+const kmPerDegree = earthRadiusKm * math.Pi / 180
+
+type cell struct {
+	row, col int
+}
+
+// SpatialIndex buckets places with a known location into a regular grid of
+// lat/long cells. Nearest-neighbour searches expand outwards ring-by-ring
+// from the query cell, stopping once no unvisited cell could possibly hold
+// a closer place.
+type SpatialIndex struct {
+	cellSize  float64 // degrees
+	cells     map[cell][]*Place
+	minRow    int
+	maxRow    int
+	minCol    int
+	maxCol    int
+	lonFactor float64 // conservative km-per-degree of longitude across the indexed extent
+	numPlaces int
+}
+
+type Neighbour struct {
+	Place      *Place
+	DistanceKm float64
+}
+
+func NewSpatialIndex(places []*Place, cellSize float64) *SpatialIndex {
+	idx := &SpatialIndex{
+		cellSize: cellSize,
+		cells:    make(map[cell][]*Place),
+		minRow:   math.MaxInt,
+		maxRow:   math.MinInt,
+		minCol:   math.MaxInt,
+		maxCol:   math.MinInt,
+	}
+
+	maxAbsLat := 0.0
+	for _, place := range places {
+		if !place.HasLocation() {
+			continue
+		}
+		c := idx.cellFor(place.Lat, place.Long)
+		idx.cells[c] = append(idx.cells[c], place)
+		idx.minRow, idx.maxRow = min(idx.minRow, c.row), max(idx.maxRow, c.row)
+		idx.minCol, idx.maxCol = min(idx.minCol, c.col), max(idx.maxCol, c.col)
+		maxAbsLat = max(maxAbsLat, math.Abs(place.Lat))
+		idx.numPlaces++
+	}
+	idx.lonFactor = kmPerDegree * math.Cos(toRadians(min(89, maxAbsLat+cellSize)))
+
+	return idx
+}
+
+// Len returns the number of located places held in the index.
+func (idx *SpatialIndex) Len() int {
+	return idx.numPlaces
+}
+
+func (idx *SpatialIndex) cellFor(lat, long float64) cell {
+	return cell{
+		row: int(math.Floor(lat / idx.cellSize)),
+		col: int(math.Floor(long / idx.cellSize)),
+	}
+}
+
+// Nearest returns up to k places closest to the given point, ordered by
+// increasing distance. A positive maxKm limits the search radius, and an
+// optional accept predicate excludes places from the results.
+func (idx *SpatialIndex) Nearest(lat, long float64, k int, maxKm float64, accept func(*Place) bool) []Neighbour {
+	results, _ := idx.nearest(lat, long, k, maxKm, accept)
+	return results
+}
+
+// nearest is Nearest, also returning the number of rings of cells searched.
+func (idx *SpatialIndex) nearest(lat, long float64, k int, maxKm float64, accept func(*Place) bool) ([]Neighbour, int) {
+	if k <= 0 || idx.numPlaces == 0 {
+		return []Neighbour{}, 0
+	}
+
+	// farthest neighbour is lowest priority, so gets evicted first
+	found := NewMinHeap(func(a, b Neighbour) bool {
+		if a.DistanceKm == b.DistanceKm {
+			return lessRelevant(a.Place, b.Place)
+		}
+		return a.DistanceKm > b.DistanceKm
+	})
+
+	// rings which don't reach the indexed extent are empty, so a point far
+	// outside it starts from the first ring which does
+	centre := idx.cellFor(lat, long)
+	minRing := max(0,
+		idx.minRow-centre.row, centre.row-idx.maxRow,
+		idx.minCol-centre.col, centre.col-idx.maxCol,
+	)
+	maxRing := max(
+		centre.row-idx.minRow, idx.maxRow-centre.row,
+		centre.col-idx.minCol, idx.maxCol-centre.col,
+	)
+
+	rings := 0
+	for ring := minRing; ring <= maxRing; ring++ {
+		rings++
+		idx.visitRing(centre, ring, func(place *Place) {
+			if accept != nil && !accept(place) {
+				return
+			}
+			dist := HaversineKm(lat, long, place.Lat, place.Long)
+			if maxKm > 0 && dist > maxKm {
+				return
+			}
+			found.PushBounded(Neighbour{Place: place, DistanceKm: dist}, k)
+		})
+
+		// anything in a cell beyond this ring is at least this far away
+		bound := float64(ring) * idx.cellSize * min(kmPerDegree, idx.lonFactor)
+		if maxKm > 0 && bound > maxKm {
+			break
+		}
+		if worst, ok := found.Top(); ok && found.Len() == k && worst.DistanceKm <= bound {
+			break
+		}
+	}
+
+	results := make([]Neighbour, found.Len())
+	copy(results, found.Items())
+	sort.SliceStable(results, func(i, j int) bool {
+		return found.less(results[j], results[i])
+	})
+	return results, rings
+}
+
+// visitRing visits the places in the cells of the ring around centre,
+// skipping the cells outside the indexed extent.
+func (idx *SpatialIndex) visitRing(centre cell, ring int, visit func(*Place)) {
+	visitCell := func(row, col int) {
+		for _, place := range idx.cells[cell{row: row, col: col}] {
+			visit(place)
+		}
+	}
+
+	if ring == 0 {
+		visitCell(centre.row, centre.col)
+		return
+	}
+
+	visitRow := func(row int) {
+		if row < idx.minRow || row > idx.maxRow {
+			return
+		}
+		for col := max(centre.col-ring, idx.minCol); col <= min(centre.col+ring, idx.maxCol); col++ {
+			visitCell(row, col)
+		}
+	}
+	visitCol := func(col int) {
+		if col < idx.minCol || col > idx.maxCol {
+			return
+		}
+		for row := max(centre.row-ring+1, idx.minRow); row <= min(centre.row+ring-1, idx.maxRow); row++ {
+			visitCell(row, col)
+		}
+	}
+	visitRow(centre.row - ring)
+	visitRow(centre.row + ring)
+	visitCol(centre.col - ring)
+	visitCol(centre.col + ring)
+}
+
+// Within returns all places inside the given bounding box (inclusive).
+func (idx *SpatialIndex) Within(minLat, minLong, maxLat, maxLong float64) []*Place {
+	results := make([]*Place, 0)
+	lo := idx.cellFor(minLat, minLong)
+	hi := idx.cellFor(maxLat, maxLong)
+
+	for row := max(lo.row, idx.minRow); row <= min(hi.row, idx.maxRow); row++ {
+		for col := max(lo.col, idx.minCol); col <= min(hi.col, idx.maxCol); col++ {
+			for _, place := range idx.cells[cell{row: row, col: col}] {
+				if place.Lat >= minLat && place.Lat <= maxLat && place.Long >= minLong && place.Long <= maxLong {
+					results = append(results, place)
+				}
+			}
+		}
+	}
+	return results
+}
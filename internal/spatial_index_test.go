@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"testing"
+)
+
+func testPlaces() []*Place {
+	return []*Place{
+		{Name: "London", Relevancy: 1.0, Lat: 51.5072, Long: -0.1276},
+		{Name: "Croydon", Relevancy: 0.7, Lat: 51.3762, Long: -0.0982},
+		{Name: "Watford", Relevancy: 0.6, Lat: 51.6565, Long: -0.3903},
+		{Name: "Oxford", Relevancy: 0.9, Lat: 51.7520, Long: -1.2577},
+		{Name: "Edinburgh", Relevancy: 0.95, Lat: 55.9533, Long: -3.1883},
+		{Name: "Nowhere", Relevancy: 0.1},
+	}
+}
+
+func TestSpatialIndex(t *testing.T) {
+	idx := NewSpatialIndex(testPlaces(), 0.1)
+
+	t.Run("skips places without a location", func(t *testing.T) {
+		if idx.Len() != 5 {
+			t.Errorf("expected 5 located places, got %d", idx.Len())
+		}
+	})
+
+	t.Run("nearest ordered by distance", func(t *testing.T) {
+		results := idx.Nearest(51.5, -0.12, 3, 0, nil)
+		expected := []string{"London", "Croydon", "Watford"}
+		if len(results) != len(expected) {
+			t.Fatalf("expected %d results, got %d", len(expected), len(results))
+		}
+		for i, name := range expected {
+			if results[i].Place.Name != name {
+				t.Errorf("result %d: expected %s, got %s", i, name, results[i].Place.Name)
+			}
+		}
+		if results[0].DistanceKm > 1.5 {
+			t.Errorf("expected London to be within 1.5km, got %.2f", results[0].DistanceKm)
+		}
+	})
+
+	t.Run("nearest finds far away places", func(t *testing.T) {
+		results := idx.Nearest(56.0, -3.2, 6, 0, nil)
+		if len(results) != 5 {
+			t.Fatalf("expected 5 results, got %d", len(results))
+		}
+		if results[0].Place.Name != "Edinburgh" || results[4].Place.Name != "Croydon" {
+			t.Errorf("unexpected ordering: first %s, last %s", results[0].Place.Name, results[4].Place.Name)
+		}
+	})
+
+	t.Run("nearest within radius", func(t *testing.T) {
+		results := idx.Nearest(51.5, -0.12, 10, 20, nil)
+		if len(results) != 2 {
+			t.Fatalf("expected 2 results within 20km, got %d", len(results))
+		}
+	})
+
+	t.Run("nearest with predicate", func(t *testing.T) {
+		results := idx.Nearest(51.5, -0.12, 1, 0, func(p *Place) bool { return p.Relevancy > 0.8 && p.Name != "London" })
+		if len(results) != 1 || results[0].Place.Name != "Oxford" {
+			t.Errorf("expected Oxford, got %v", results)
+		}
+	})
+
+	t.Run("within bounding box", func(t *testing.T) {
+		results := idx.Within(51.3, -0.5, 51.7, 0.0)
+		if len(results) != 3 {
+			t.Errorf("expected 3 results, got %d", len(results))
+		}
+	})
+}
+
+func TestSpatialIndexNearestFarOutside(t *testing.T) {
+	// a UK-sized grid of places, queried from the far side of the world
+	var places []*Place
+	for lat := 49.9; lat < 60.9; lat += 0.05 {
+		for long := -8.0; long < 1.8; long += 0.05 {
+			places = append(places, &Place{Name: "Grid", Lat: lat, Long: long})
+		}
+	}
+	idx := NewSpatialIndex(places, 0.1)
+
+	// the search starts at the edge of the index, so can't take more rings
+	// than the index is cells across
+	results, rings := idx.nearest(-89, 179, 3, 0, nil)
+	if extent := max(idx.maxRow-idx.minRow, idx.maxCol-idx.minCol) + 1; rings > extent {
+		t.Errorf("expected a query outside the index to search at most %d rings, searched %d", extent, rings)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	// no place is closer than those found
+	for _, place := range places {
+		if d := HaversineKm(-89, 179, place.Lat, place.Long); d < results[0].DistanceKm-1e-9 {
+			t.Fatalf("found %.1fkm away, but a place is %.1fkm away", results[0].DistanceKm, d)
+		}
+	}
+}
@@ -1,35 +1,34 @@
 package internal
 
 import (
+	"container/heap"
+	"context"
 	"fmt"
+	"iter"
 	"log"
 	"sort"
 	"strings"
 )
 
-type Place struct {
-	Name      string
-	Relevancy float64
-}
-
 type TrieNode struct {
-	Children map[rune]*TrieNode
-	Places   *MinHeap[*Place] // Store pointers instead of values to reduce memory duplication
+	Children  map[rune]*TrieNode
+	Places    *MinHeap[*Place] // Store pointers instead of values to reduce memory duplication
+	Terminals []*Place         // Places whose name ends at this node
+	Best      *Place           // Most relevant place anywhere in this subtree
+	Count     int              // Number of places anywhere in this subtree
 }
 
 type Trie struct {
-	root *TrieNode
-	less func(a, b *Place) bool
-	topK int
+	root    *TrieNode
+	less    func(a, b *Place) bool
+	topK    int
+	places  []*Place
+	byID    map[string]*Place
+	version string
 }
 
 func NewTrie(maxPerNode int) *Trie {
-	less := func(a, b *Place) bool {
-		if a.Relevancy == b.Relevancy {
-			return len(a.Name) > len(b.Name)
-		}
-		return a.Relevancy < b.Relevancy
-	}
+	less := lessRelevant
 	return &Trie{
 		root: &TrieNode{
 			Children: make(map[rune]*TrieNode),
@@ -37,6 +36,7 @@ func NewTrie(maxPerNode int) *Trie {
 		},
 		less: less,
 		topK: maxPerNode,
+		byID: make(map[string]*Place),
 	}
 }
 
@@ -44,7 +44,41 @@ func (t *Trie) TopK() int {
 	return t.topK
 }
 
+// Version identifies the dataset the trie was populated from, if known.
+func (t *Trie) Version() string {
+	return t.version
+}
+
+// Places returns every place inserted into the trie, in insertion order.
+func (t *Trie) Places() []*Place {
+	return t.places
+}
+
+// FindByID returns the place with the given ID, if any.
+func (t *Trie) FindByID(id string) (*Place, bool) {
+	place, ok := t.byID[id]
+	return place, ok
+}
+
+// Insert adds the place to the trie, generating an ID if it doesn't have
+// one. Clashing IDs are disambiguated with a numeric suffix; generated IDs
+// only clash for rows which are identical, so it doesn't matter which gets
+// which.
 func (t *Trie) Insert(place *Place) {
+	if place.ID == "" {
+		place.ID = generateID(place)
+	}
+	if _, exists := t.byID[place.ID]; exists {
+		base := place.ID
+		for n := 2; exists; n++ {
+			place.ID = fmt.Sprintf("%s-%d", base, n)
+			_, exists = t.byID[place.ID]
+		}
+	}
+	t.byID[place.ID] = place
+
+	place.seq = len(t.places)
+	t.places = append(t.places, place)
 	node := t.root
 	lower := strings.ToLower(place.Name)
 
@@ -56,21 +90,58 @@ func (t *Trie) Insert(place *Place) {
 			}
 		}
 		node = node.Children[r]
+		node.Count++
 		node.Places.PushBounded(place, t.topK)
+		if node.Best == nil || t.less(node.Best, place) {
+			node.Best = place
+		}
 	}
+	node.Terminals = append(node.Terminals, place)
 }
 
-func (t *Trie) FindByPrefix(prefix string) []*Place {
+func (t *Trie) findNode(prefix string) *TrieNode {
 	node := t.root
 	lower := strings.ToLower(prefix)
 	for _, r := range lower {
 		next := node.Children[r]
 		if next == nil {
-			return []*Place{}
+			return nil
 		}
 		node = next
 	}
+	return node
+}
+
+// FindByName returns the places whose name exactly matches (ignoring case).
+func (t *Trie) FindByName(name string) []*Place {
+	node := t.findNode(name)
+	if node == nil || node == t.root {
+		return []*Place{}
+	}
+	result := make([]*Place, len(node.Terminals))
+	copy(result, node.Terminals)
+	return result
+}
 
+// CountPrefix returns the total number of places starting with the prefix,
+// not just the top-K held at its node.
+func (t *Trie) CountPrefix(prefix string) int {
+	node := t.findNode(prefix)
+	if node == nil || node == t.root {
+		return 0
+	}
+	return node.Count
+}
+
+func (t *Trie) FindByPrefix(prefix string) []*Place {
+	node := t.findNode(prefix)
+	if node == nil {
+		return []*Place{}
+	}
+	return t.sortedTopK(node)
+}
+
+func (t *Trie) sortedTopK(node *TrieNode) []*Place {
 	items := node.Places.Items()
 	result := make([]*Place, len(items))
 	copy(result, items)
@@ -82,10 +153,124 @@ func (t *Trie) FindByPrefix(prefix string) []*Place {
 	return result
 }
 
+// Walk yields every place matching the prefix in relevancy order. The
+// node's stored top-K are yielded first; if the subtree holds more places
+// than that, a best-first traversal (using each node's Best place as an
+// upper bound for its subtree) continues from where the top-K left off.
+func (t *Trie) Walk(prefix string) iter.Seq[*Place] {
+	node := t.findNode(prefix)
+	if node == nil || node == t.root {
+		return func(func(*Place) bool) {}
+	}
+	return t.walkNode(node)
+}
+
+func (t *Trie) walkNode(node *TrieNode) iter.Seq[*Place] {
+	return func(yield func(*Place) bool) {
+		topK := t.sortedTopK(node)
+		for _, place := range topK {
+			if !yield(place) {
+				return
+			}
+		}
+		if len(topK) < t.topK {
+			return // the heap holds the entire subtree
+		}
+
+		last := topK[len(topK)-1]
+		for place := range t.bestFirst(node) {
+			// skip over anything already yielded from the top-K
+			if !t.less(place, last) {
+				continue
+			}
+			if !yield(place) {
+				return
+			}
+		}
+	}
+}
+
+// FindByPrefixFunc returns up to limit places matching the prefix which are
+// accepted by the predicate, in relevancy order. Unlike FindByPrefix, it
+// isn't limited to the node's top-K, so always returns a full page if there
+// are enough matching places.
+func (t *Trie) FindByPrefixFunc(prefix string, limit int, accept func(*Place) bool) []*Place {
+	results, _ := t.FindByPrefixContext(context.Background(), prefix, limit, accept)
+	return results
+}
+
+// walkCheckInterval is how many places are walked between checks for
+// cancellation
+const walkCheckInterval = 64
+
+// FindByPrefixContext is FindByPrefixFunc for searches that may need to be
+// abandoned part way, as a selective predicate can walk much of the trie.
+func (t *Trie) FindByPrefixContext(ctx context.Context, prefix string, limit int, accept func(*Place) bool) ([]*Place, error) {
+	results := make([]*Place, 0, max(limit, 0))
+	if limit <= 0 {
+		return results, nil
+	}
+	walked := 0
+	for place := range t.Walk(prefix) {
+		if walked++; walked%walkCheckInterval == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if accept == nil || accept(place) {
+			results = append(results, place)
+			if len(results) == limit {
+				break
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+type walkEntry struct {
+	node  *TrieNode // either a subtree still to be expanded...
+	place *Place    // ...or a place ready to be yielded
+}
+
+func (e walkEntry) key() *Place {
+	if e.place != nil {
+		return e.place
+	}
+	return e.node.Best
+}
+
+func (t *Trie) bestFirst(start *TrieNode) iter.Seq[*Place] {
+	return func(yield func(*Place) bool) {
+		// most relevant entry at the top of the heap
+		pq := NewMinHeap(func(a, b walkEntry) bool {
+			return t.less(b.key(), a.key())
+		})
+		heap.Push(pq, walkEntry{node: start})
+
+		for pq.Len() > 0 {
+			entry := heap.Pop(pq).(walkEntry)
+			if entry.place != nil {
+				if !yield(entry.place) {
+					return
+				}
+				continue
+			}
+
+			for _, place := range entry.node.Terminals {
+				heap.Push(pq, walkEntry{place: place})
+			}
+			for _, child := range entry.node.Children {
+				heap.Push(pq, walkEntry{node: child})
+			}
+		}
+	}
+}
+
 func PopulateFrom(filename string, topK int) (*Trie, error) {
 	trie := NewTrie(topK)
-	count, err := LoadCSV(filename, func(location string, score float64) error {
-		trie.Insert(&Place{Name: location, Relevancy: score})
+	count, err := LoadCSV(filename, func(place *Place) error {
+		trie.Insert(place)
 		return nil
 	})
 
@@ -94,5 +279,10 @@ func PopulateFrom(filename string, topK int) (*Trie, error) {
 	}
 	log.Printf("Loaded %d place names into trie structure", count)
 
+	if trie.version, err = FileVersion(filename); err != nil {
+		return nil, fmt.Errorf("failed to determine dataset version: %w", err)
+	}
+	log.Printf("Dataset version: %s", trie.version)
+
 	return trie, nil
 }
@@ -1,6 +1,10 @@
 package internal
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"testing"
 )
 
@@ -206,3 +210,182 @@ func TestTrieAdvanced(t *testing.T) {
 		}
 	})
 }
+
+func TestTrieWalk(t *testing.T) {
+	// 20 places starting "Ba", but each node only keeps its top 5
+	newTrie := func() *Trie {
+		trie := NewTrie(5)
+		for i := range 20 {
+			country := "England"
+			if i%4 == 0 {
+				country = "Wales"
+			}
+			trie.Insert(&Place{
+				Name:      fmt.Sprintf("Ba%c", 'a'+i),
+				Relevancy: float64(20-i) / 20,
+				Country:   country,
+			})
+		}
+		trie.Insert(&Place{Name: "Ba", Relevancy: 0.01})
+		return trie
+	}
+
+	t.Run("walk yields every place in relevancy order", func(t *testing.T) {
+		trie := newTrie()
+		var results []*Place
+		for place := range trie.Walk("Ba") {
+			results = append(results, place)
+		}
+
+		if len(results) != 21 {
+			t.Fatalf("expected 21 results, got %d", len(results))
+		}
+		for i := 1; i < len(results); i++ {
+			if results[i].Relevancy > results[i-1].Relevancy {
+				t.Errorf("result %d (%s) is more relevant than result %d (%s)", i, results[i].Name, i-1, results[i-1].Name)
+			}
+		}
+		if results[20].Name != "Ba" {
+			t.Errorf("expected the least relevant place to be Ba, got %s", results[20].Name)
+		}
+	})
+
+	t.Run("walk stops early", func(t *testing.T) {
+		trie := newTrie()
+		count := 0
+		for range trie.Walk("Ba") {
+			count++
+			if count == 7 {
+				break
+			}
+		}
+		if count != 7 {
+			t.Errorf("expected to stop after 7 results, got %d", count)
+		}
+	})
+
+	t.Run("walk with empty or unknown prefix", func(t *testing.T) {
+		trie := newTrie()
+		for _, prefix := range []string{"", "X"} {
+			for place := range trie.Walk(prefix) {
+				t.Errorf("expected no results for %q, got %s", prefix, place.Name)
+			}
+		}
+	})
+
+	t.Run("count includes places beyond the top-K", func(t *testing.T) {
+		trie := newTrie()
+		if count := trie.CountPrefix("Ba"); count != 21 {
+			t.Errorf("expected 21 places under Ba, got %d", count)
+		}
+		if count := trie.CountPrefix("Bab"); count != 1 {
+			t.Errorf("expected 1 place under Bab, got %d", count)
+		}
+		for _, prefix := range []string{"", "X"} {
+			if count := trie.CountPrefix(prefix); count != 0 {
+				t.Errorf("expected no places for %q, got %d", prefix, count)
+			}
+		}
+	})
+
+	t.Run("filter returns a full page beyond the top-K", func(t *testing.T) {
+		trie := newTrie()
+		filter := PlaceFilter{Country: "wales"}
+		results := trie.FindByPrefixFunc("Ba", 5, filter.Predicate())
+		if len(results) != 5 {
+			t.Fatalf("expected 5 results, got %d", len(results))
+		}
+
+		expected := []string{"Baa", "Bae", "Bai", "Bam", "Baq"}
+		for i, name := range expected {
+			if results[i].Name != name {
+				t.Errorf("result %d: expected %s, got %s", i, name, results[i].Name)
+			}
+		}
+	})
+	t.Run("cancelled search is abandoned", func(t *testing.T) {
+		trie := newTrie()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := trie.FindByPrefixContext(ctx, "Ba", 5, nil); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+}
+
+func TestTrieFindByID(t *testing.T) {
+	trie := NewTrie(10)
+	places := []*Place{
+		{ID: "IPN0001", Name: "London", Relevancy: 1.0},
+		{Name: "Newport", Relevancy: 0.8, Lat: 51.5842, Long: -2.9977},
+		{Name: "Newport", Relevancy: 0.5, Lat: 50.7010, Long: -1.2883},
+		{ID: "IPN0001", Name: "Duplicate", Relevancy: 0.1},
+	}
+	for _, p := range places {
+		trie.Insert(p)
+	}
+
+	t.Run("supplied IDs are kept", func(t *testing.T) {
+		place, ok := trie.FindByID("IPN0001")
+		if !ok || place.Name != "London" {
+			t.Errorf("expected to find London, got %v", place)
+		}
+	})
+
+	t.Run("generated IDs are stable and distinct", func(t *testing.T) {
+		if places[1].ID == "" || places[1].ID == places[2].ID {
+			t.Fatalf("expected distinct generated IDs, got %q and %q", places[1].ID, places[2].ID)
+		}
+		if places[1].ID != generateID(&Place{Name: "Newport", Relevancy: 0.8, Lat: 51.5842, Long: -2.9977}) {
+			t.Errorf("expected generated ID to be derived from the row, got %q", places[1].ID)
+		}
+		if place, ok := trie.FindByID(places[2].ID); !ok || place != places[2] {
+			t.Errorf("expected to find the second Newport by ID")
+		}
+	})
+
+	t.Run("generated IDs survive reordering", func(t *testing.T) {
+		rows := func() []*Place {
+			return []*Place{
+				{Name: "Wick", Relevancy: 0.7, Country: "Scotland"},
+				{Name: "Wick", Relevancy: 0.5, Country: "England"},
+				{Name: "Wick", Relevancy: 0.5, Country: "Wales"},
+				{Name: "Wick", Relevancy: 0.4},
+			}
+		}
+		ids := func(places []*Place) map[string]string {
+			trie := NewTrie(10)
+			byRow := make(map[string]string)
+			for _, p := range places {
+				trie.Insert(p)
+				byRow[fmt.Sprintf("%g|%s", p.Relevancy, p.Country)] = p.ID
+			}
+			return byRow
+		}
+
+		original := ids(rows())
+		reordered := rows()
+		slices.Reverse(reordered)
+		added := append([]*Place{{Name: "Wick", Relevancy: 0.6}}, rows()...)
+		for _, other := range []map[string]string{ids(reordered), ids(added)} {
+			for row, id := range original {
+				if other[row] != id {
+					t.Errorf("%s: expected ID %s to be kept, got %s", row, id, other[row])
+				}
+			}
+		}
+	})
+
+	t.Run("clashing IDs are disambiguated", func(t *testing.T) {
+		place, ok := trie.FindByID("IPN0001-2")
+		if !ok || place.Name != "Duplicate" {
+			t.Errorf("expected to find Duplicate as IPN0001-2, got %v", place)
+		}
+	})
+
+	t.Run("unknown ID", func(t *testing.T) {
+		if _, ok := trie.FindByID("nope"); ok {
+			t.Error("expected not to find an unknown ID")
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package internal
+
+import "context"
+
+// Keystroke is one request in a search-as-you-type session. Its context is
+// cancelled as soon as a newer keystroke arrives.
+type Keystroke[T any] struct {
+	Ctx     context.Context
+	Request T
+}
+
+// Typeahead hands the keystrokes of a search-as-you-type session, read on
+// one goroutine, to a worker on another. Only the latest keystroke is
+// queued: one superseded before the worker gets to it is dropped, and one
+// superseded mid-search has its context cancelled, so the worker never
+// falls behind a fast typist.
+type Typeahead[T any] struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	latest chan Keystroke[T]
+}
+
+func NewTypeahead[T any](ctx context.Context) *Typeahead[T] {
+	return &Typeahead[T]{
+		ctx:    ctx,
+		cancel: func() {},
+		latest: make(chan Keystroke[T], 1),
+	}
+}
+
+// Push queues a keystroke, superseding any earlier ones. It never blocks,
+// but must only be called from one goroutine.
+func (t *Typeahead[T]) Push(req T) {
+	t.cancel()
+	var ctx context.Context
+	ctx, t.cancel = context.WithCancel(t.ctx)
+
+	select {
+	case <-t.latest: // superseded before its search started
+	default:
+	}
+	t.latest <- Keystroke[T]{Ctx: ctx, Request: req}
+}
+
+// Close ends the session once the latest keystroke has been handled. It
+// must be called from the same goroutine as Push.
+func (t *Typeahead[T]) Close() {
+	close(t.latest)
+}
+
+// Keystrokes is read by the worker, and is closed by Close.
+func (t *Typeahead[T]) Keystrokes() <-chan Keystroke[T] {
+	return t.latest
+}
@@ -0,0 +1,42 @@
+package internal
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTypeahead(t *testing.T) {
+	t.Run("superseded keystrokes are dropped and cancelled", func(t *testing.T) {
+		session := NewTypeahead[string](context.Background())
+		session.Push("l")
+		first := <-session.Keystrokes()
+		session.Push("lo")
+		session.Push("lon")
+		session.Close()
+
+		if first.Ctx.Err() == nil {
+			t.Error("expected the in-flight keystroke to be cancelled")
+		}
+		var got []string
+		for keystroke := range session.Keystrokes() {
+			if keystroke.Ctx.Err() != nil {
+				t.Errorf("expected the latest keystroke to be live, got %v", keystroke.Ctx.Err())
+			}
+			got = append(got, keystroke.Request)
+		}
+		if len(got) != 1 || got[0] != "lon" {
+			t.Errorf("expected only lon to be queued, got %v", got)
+		}
+	})
+
+	t.Run("ending the session cancels the latest keystroke", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		session := NewTypeahead[string](ctx)
+		session.Push("l")
+		keystroke := <-session.Keystrokes()
+		cancel()
+		if keystroke.Ctx.Err() == nil {
+			t.Error("expected the keystroke to be cancelled with the session")
+		}
+	})
+}
@@ -8,8 +8,10 @@ import (
 func main() {
 	var filePath string
 	var port int
+	var grpcPort int
 	var debug bool
 	var topK int
+	var publicURL string
 
 	rootCmd := &cobra.Command{
 		Use:  "placenames",
@@ -17,14 +19,16 @@ func main() {
 	}
 
 	apiServerCmd := &cobra.Command{
-		Use:   "api-server [--file <path>] [--port <port>] [--debug] [--top-k <k>]",
+		Use:   "api-server [--file <path>] [--port <port>] [--grpc-port <port>] [--debug] [--top-k <k>] [--public-url <url>]",
 		Short: "Start HTTP API server",
 		RunE: func(_ *cobra.Command, _ []string) error {
-			return cmd.ApiServer(filePath, port, debug, topK)
+			return cmd.ApiServer(filePath, port, grpcPort, debug, topK, publicURL)
 		},
 	}
 	apiServerCmd.Flags().IntVar(&port, "port", 8080, "Port to run HTTP server on")
+	apiServerCmd.Flags().IntVar(&grpcPort, "grpc-port", 0, "Port to run gRPC server on (0, the default, disables it)")
 	apiServerCmd.Flags().IntVar(&topK, "top-k", 100, "Number of top results to store per prefix node")
+	apiServerCmd.Flags().StringVar(&publicURL, "public-url", "", "Scheme and host clients reach the API on, e.g. https://places.example.com (default: taken from each request)")
 	apiServerCmd.Flags().BoolVar(&debug, "debug", false, "Enable debugging (pprof) - WARING: do not enable in production")
 	apiServerCmd.PersistentFlags().StringVar(&filePath, "file", "./data/placenames_with_relevancy.csv.gz", "Path to place names data file")
 
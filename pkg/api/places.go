@@ -0,0 +1,121 @@
+// Package api holds the Place Names API's request and response types,
+// shared by the server and the Go client so they can't drift apart. It
+// depends only on the standard library, so importing it (or the client)
+// doesn't pull in the server.
+package api
+
+import "encoding/json"
+
+// Search modes, selected with ?mode=
+const (
+	ModeAuto       = "auto"
+	ModePrefix     = "prefix"
+	ModeExact      = "exact"
+	ModeCoordinate = "coordinate"
+	ModeGridRef    = "grid_ref"
+
+	// ModeWhat3Words is only ever detected, never requested
+	ModeWhat3Words = "what3words"
+)
+
+// Ranking options, selected with ?rank=
+const (
+	RankRelevancy = "relevancy"
+	RankBlended   = "blended"
+)
+
+// Optional fields which can be requested with ?fields=a,b,c
+const (
+	FieldGridRef = "grid_ref"
+	FieldGeohash = "geohash"
+)
+
+// How the query was interpreted
+const (
+	MatchTypePrefix     = "prefix"
+	MatchTypeExact      = "exact"
+	MatchTypeCoordinate = "coordinate"
+	MatchTypeGridRef    = "grid_ref"
+	MatchTypeNearby     = "nearby"
+	MatchTypePolygon    = "polygon"
+)
+
+type Result struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Relevancy  float64  `json:"relevancy"`
+	Type       string   `json:"type,omitempty"`
+	DistanceKm *float64 `json:"distance_km,omitempty"`
+	GridRef    string   `json:"grid_ref,omitempty"`
+	Geohash    string   `json:"geohash,omitempty"`
+}
+
+type PlaceResponse struct {
+	MatchType  string   `json:"match_type"`
+	Results    []Result `json:"results"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+
+	Meta *ResponseMeta `json:"meta,omitempty"`
+}
+
+// ResponseMeta is the optional envelope, requested with ?envelope=true
+type ResponseMeta struct {
+	Query          string  `json:"query"` // as normalized for the match mode
+	Mode           string  `json:"mode"`
+	MaxResults     int     `json:"max_results"`
+	Offset         int     `json:"offset"`
+	Total          *int    `json:"total,omitempty"` // candidates at the matched node, before filtering
+	Truncated      bool    `json:"truncated"`
+	DatasetVersion string  `json:"dataset_version,omitempty"`
+	TookMs         float64 `json:"took_ms"`
+}
+
+// PlaceDetail is the full record held for a place
+type PlaceDetail struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	Relevancy      float64  `json:"relevancy"`
+	Type           string   `json:"type,omitempty"`
+	Country        string   `json:"country,omitempty"`
+	Region         string   `json:"region,omitempty"`
+	County         string   `json:"county,omitempty"`
+	LocalAuthority string   `json:"lad,omitempty"`
+	NationalPark   string   `json:"npark,omitempty"`
+	Lat            *float64 `json:"lat,omitempty"`
+	Long           *float64 `json:"long,omitempty"`
+	Easting        *float64 `json:"easting,omitempty"`
+	Northing       *float64 `json:"northing,omitempty"`
+	GridRef        string   `json:"grid_ref,omitempty"`
+	Geohash        string   `json:"geohash,omitempty"`
+}
+
+type LookupRequest struct {
+	IDs []string `json:"ids" binding:"required"`
+}
+
+type LookupResponse struct {
+	Results  []PlaceDetail `json:"results"`
+	NotFound []string      `json:"not_found"`
+}
+
+type DistanceResponse struct {
+	From           PlaceDetail `json:"from"`
+	To             PlaceDetail `json:"to"`
+	DistanceKm     float64     `json:"distance_km"`
+	DistanceMiles  float64     `json:"distance_miles"`
+	BearingDegrees float64     `json:"bearing_degrees"`
+	Compass        string      `json:"compass"`
+}
+
+// Geometry is a GeoJSON Polygon or MultiPolygon
+type Geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// SearchRequest is the body of POST /v1/place-names/search
+type SearchRequest struct {
+	Prefix     string   `json:"prefix"`
+	MaxResults int      `json:"max_results"`
+	Geometry   Geometry `json:"geometry" binding:"required"`
+}
@@ -0,0 +1,44 @@
+package api
+
+// Errors are returned as RFC 7807 problem details. Clients should branch on
+// the code (also the last part of the type URI), never on the detail text.
+
+// Problem codes
+const (
+	CodeInvalidParameter     = "invalid_parameter"
+	CodeInvalidBody          = "invalid_body"
+	CodeBodyTooLarge         = "body_too_large"
+	CodeUnsupportedQuery     = "unsupported_query"
+	CodeNotFound             = "not_found"
+	CodeAmbiguousPlace       = "ambiguous_place"
+	CodeNoLocation           = "no_location"
+	CodeRouteNotFound        = "route_not_found"
+	CodeMethodNotAllowed     = "method_not_allowed"
+	CodeUpgradeRequired      = "upgrade_required"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeInternalError        = "internal_error"
+)
+
+type Problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	Code          string         `json:"code"`
+	InvalidParams []InvalidParam `json:"invalid_params,omitempty"`
+	RequestID     string         `json:"request_id,omitempty"`
+
+	// Candidates is only set for ambiguous_place and not_found problems
+	// from the distance endpoint
+	Candidates map[string][]PlaceDetail `json:"candidates,omitempty"`
+}
+
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+func (p *Problem) Error() string {
+	return p.Detail
+}
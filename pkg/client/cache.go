@@ -0,0 +1,91 @@
+package client
+
+import (
+	"container/list"
+	"slices"
+	"sync"
+	"time"
+)
+
+// responseCache is a small LRU cache of search responses, with entries
+// expiring after a fixed TTL. Responses are copied in and out, so callers
+// can't change what's cached for everyone else.
+type responseCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ttl        time.Duration
+	now        func() time.Time
+	order      *list.List // most recently used at the front
+	entries    map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key     string
+	value   *PlaceResponse
+	expires time.Time
+}
+
+func newResponseCache(maxEntries int, ttl time.Duration) *responseCache {
+	return &responseCache{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		now:        time.Now,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+func (c *responseCache) get(key string) (*PlaceResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if c.now().After(entry.expires) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return clonePlaceResponse(entry.value), true
+}
+
+func (c *responseCache) put(key string, value *PlaceResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cacheEntry{key: key, value: clonePlaceResponse(value), expires: c.now().Add(c.ttl)}
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// clonePlaceResponse deep copies the response
+func clonePlaceResponse(resp *PlaceResponse) *PlaceResponse {
+	clone := *resp
+	clone.Results = slices.Clone(resp.Results)
+	for i, result := range clone.Results {
+		if result.DistanceKm != nil {
+			clone.Results[i].DistanceKm = new(*result.DistanceKm)
+		}
+	}
+	if resp.Meta != nil {
+		meta := *resp.Meta
+		if meta.Total != nil {
+			meta.Total = new(*meta.Total)
+		}
+		clone.Meta = &meta
+	}
+	return &clone
+}
@@ -0,0 +1,276 @@
+// Package client is the Go client for the Place Names API.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried: network errors,
+// 429 and 5xx responses are retried with exponential backoff and jitter,
+// honouring any Retry-After header.
+type RetryPolicy struct {
+	MaxAttempts    int // including the first; 1 disables retries
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+}
+
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	retry      RetryPolicy
+	cache      *responseCache
+	userAgent  string
+}
+
+type Option func(*Client)
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+func WithRetryPolicy(retry RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = retry
+	}
+}
+
+// WithCache caches up to maxEntries search responses for the given TTL.
+// The dataset only changes on redeploy, so a long TTL is usually safe.
+func WithCache(maxEntries int, ttl time.Duration) Option {
+	return func(c *Client) {
+		c.cache = newResponseCache(maxEntries, ttl)
+	}
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// New creates a client for the API at baseURL, e.g. "http://localhost:8080".
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL: scheme must be http or https, got %q", u.Scheme)
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: http.DefaultClient,
+		retry:      DefaultRetryPolicy,
+		userAgent:  "placenames-api-go-client",
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.retry.MaxAttempts = max(1, c.retry.MaxAttempts)
+	return c, nil
+}
+
+// Search queries GET /v1/place-names. Responses are cached when the client
+// was created WithCache.
+func (c *Client) Search(ctx context.Context, req SearchRequest) (*PlaceResponse, error) {
+	endpoint := c.endpoint(req.encode(), "v1", "place-names")
+	if c.cache != nil {
+		if resp, ok := c.cache.get(endpoint); ok {
+			return resp, nil
+		}
+	}
+
+	var resp PlaceResponse
+	if err := c.do(ctx, http.MethodGet, endpoint, nil, &resp); err != nil {
+		return nil, err
+	}
+	if c.cache != nil {
+		c.cache.put(endpoint, &resp)
+	}
+	return &resp, nil
+}
+
+// Place gets the full details of a place by ID.
+func (c *Client) Place(ctx context.Context, id string) (*PlaceDetail, error) {
+	var resp PlaceDetail
+	endpoint := c.endpoint(nil, "v1", "place-names", url.PathEscape(id))
+	if err := c.do(ctx, http.MethodGet, endpoint, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Lookup gets several places by ID; unknown IDs are listed in NotFound.
+func (c *Client) Lookup(ctx context.Context, ids []string) (*LookupResponse, error) {
+	var resp LookupResponse
+	body := map[string][]string{"ids": ids}
+	if err := c.do(ctx, http.MethodPost, c.endpoint(nil, "v1", "place-names", "lookup"), body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Nearby finds the places around the place with the given ID.
+func (c *Client) Nearby(ctx context.Context, id string, req NearbyRequest) (*PlaceResponse, error) {
+	var resp PlaceResponse
+	endpoint := c.endpoint(req.encode(), "v1", "place-names", url.PathEscape(id), "nearby")
+	if err := c.do(ctx, http.MethodGet, endpoint, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Distance gets the distance and bearing between two places, each given by
+// ID or exact name. Ambiguous names fail with ErrAmbiguousPlace, and the
+// candidates are in the Error's Problem.
+func (c *Client) Distance(ctx context.Context, from, to string) (*DistanceResponse, error) {
+	var resp DistanceResponse
+	endpoint := c.endpoint(url.Values{"from": {from}, "to": {to}}, "v1", "place-names", "distance")
+	if err := c.do(ctx, http.MethodGet, endpoint, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SearchShape finds places within a GeoJSON Polygon or MultiPolygon.
+func (c *Client) SearchShape(ctx context.Context, req ShapeRequest) (*PlaceResponse, error) {
+	var resp PlaceResponse
+	if err := c.do(ctx, http.MethodPost, c.endpoint(nil, "v1", "place-names", "search"), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// endpoint builds a URL from already-escaped path segments
+func (c *Client) endpoint(query url.Values, segments ...string) string {
+	u := c.baseURL.JoinPath(segments...)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// do sends the request, retrying as per the policy, and decodes the JSON
+// response into out. Every request is read-only, so all are safe to retry.
+func (c *Client) do(ctx context.Context, method, endpoint string, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to encode request body: %w", err)
+		}
+	}
+
+	var lastErr error
+	for attempt := range c.retry.MaxAttempts {
+		if attempt > 0 {
+			if err := c.sleep(ctx, c.backoff(attempt, lastErr)); err != nil {
+				return err
+			}
+		}
+
+		retryable, err := c.attempt(ctx, method, endpoint, payload, out)
+		if err == nil || !retryable {
+			return err
+		}
+		lastErr = err
+	}
+	return lastErr
+}
+
+func (c *Client) attempt(ctx context.Context, method, endpoint string, payload []byte, out any) (bool, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// don't retry once the caller has given up
+		return ctx.Err() == nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &Error{StatusCode: resp.StatusCode}
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr.Problem); err != nil || apiErr.Problem.Code == "" {
+			apiErr.Problem = Problem{Status: resp.StatusCode, Title: http.StatusText(resp.StatusCode)}
+		}
+		apiErr.retryAfter = parseRetryAfter(resp.Header)
+		return isRetryable(resp.StatusCode), apiErr
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return false, nil
+}
+
+func isRetryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func parseRetryAfter(header http.Header) time.Duration {
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return 0
+}
+
+// backoff doubles with each attempt, with full jitter, but never waits less
+// than the server asked for.
+func (c *Client) backoff(attempt int, lastErr error) time.Duration {
+	delay := min(c.retry.MaxBackoff, c.retry.InitialBackoff<<(attempt-1))
+	if delay > 0 {
+		delay = rand.N(delay) + 1
+	}
+	var apiErr *Error
+	if errors.As(lastErr, &apiErr) {
+		delay = max(delay, apiErr.retryAfter)
+	}
+	return delay
+}
+
+func (c *Client) sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
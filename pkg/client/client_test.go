@@ -0,0 +1,290 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/map-services/placenames-api/internal"
+	"github.com/map-services/placenames-api/internal/routes"
+)
+
+// newTestServer runs the real router over a handful of places. Every
+// request is counted, and can be intercepted before it reaches the router.
+func newTestServer(t *testing.T, intercept func(w http.ResponseWriter, r *http.Request) bool) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	trie := internal.NewTrie(10)
+	for _, place := range []*internal.Place{
+		{ID: "london", Name: "London", Relevancy: 1.0, Lat: 51.5072, Long: -0.1276},
+		{ID: "croydon", Name: "Croydon", Relevancy: 0.7, Lat: 51.3762, Long: -0.0982},
+		{ID: "oxford", Name: "Oxford", Relevancy: 0.9, Lat: 51.752, Long: -1.2577},
+		{ID: "luton", Name: "Luton", Relevancy: 0.6},
+		{ID: "newport-wales", Name: "Newport", Relevancy: 0.8, Lat: 51.5842, Long: -2.9977},
+		{ID: "newport-iow", Name: "Newport", Relevancy: 0.5, Lat: 50.701, Long: -1.2883},
+	} {
+		trie.Insert(place)
+	}
+	spatial := internal.NewSpatialIndex(trie.Places(), 0.1)
+
+	r := gin.New()
+	r.Use(routes.RequestID(), gin.CustomRecovery(routes.Recovery))
+	if err := routes.Register(r, trie, spatial, ""); err != nil {
+		t.Fatalf("failed to register routes: %v", err)
+	}
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests.Add(1)
+		if intercept != nil && intercept(w, req) {
+			return
+		}
+		r.ServeHTTP(w, req)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func newTestClient(t *testing.T, server *httptest.Server, opts ...Option) *Client {
+	t.Helper()
+	opts = append([]Option{WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond})}, opts...)
+	c, err := New(server.URL, opts...)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return c
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("search", func(t *testing.T) {
+		server, _ := newTestServer(t, nil)
+		c := newTestClient(t, server)
+
+		resp, err := c.Search(ctx, SearchRequest{Query: "lo", Envelope: true})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if resp.MatchType != "prefix" || len(resp.Results) != 1 || resp.Results[0].ID != "london" {
+			t.Errorf("expected london, got %+v", resp)
+		}
+		if resp.Meta == nil || resp.Meta.Query != "lo" {
+			t.Errorf("expected the metadata envelope, got %+v", resp.Meta)
+		}
+	})
+
+	t.Run("search pages with a cursor", func(t *testing.T) {
+		server, _ := newTestServer(t, nil)
+		c := newTestClient(t, server)
+
+		req := SearchRequest{Query: "new", MaxResults: 1}
+		var ids []string
+		for {
+			resp, err := c.Search(ctx, req)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			for _, result := range resp.Results {
+				ids = append(ids, result.ID)
+			}
+			if resp.NextCursor == "" {
+				break
+			}
+			req.Cursor = resp.NextCursor
+		}
+		if len(ids) != 2 || ids[0] != "newport-wales" || ids[1] != "newport-iow" {
+			t.Errorf("expected both Newports in relevancy order, got %v", ids)
+		}
+	})
+
+	t.Run("place, lookup, nearby and distance", func(t *testing.T) {
+		server, _ := newTestServer(t, nil)
+		c := newTestClient(t, server)
+
+		place, err := c.Place(ctx, "oxford")
+		if err != nil || place.Name != "Oxford" || place.Lat == nil {
+			t.Errorf("expected Oxford with a location, got %+v (%v)", place, err)
+		}
+
+		lookup, err := c.Lookup(ctx, []string{"london", "nope"})
+		if err != nil || len(lookup.Results) != 1 || len(lookup.NotFound) != 1 {
+			t.Errorf("expected one found and one not found, got %+v (%v)", lookup, err)
+		}
+
+		nearby, err := c.Nearby(ctx, "london", NearbyRequest{RadiusKm: 20})
+		if err != nil || len(nearby.Results) != 1 || nearby.Results[0].ID != "croydon" {
+			t.Errorf("expected Croydon near London, got %+v (%v)", nearby, err)
+		}
+
+		distance, err := c.Distance(ctx, "London", "oxford")
+		if err != nil || distance.DistanceKm < 80 || distance.DistanceKm > 90 {
+			t.Errorf("expected London to Oxford to be ~83km, got %+v (%v)", distance, err)
+		}
+	})
+
+	t.Run("typed errors", func(t *testing.T) {
+		server, _ := newTestServer(t, nil)
+		c := newTestClient(t, server)
+
+		_, err := c.Place(ctx, "nope")
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+
+		_, err = c.Search(ctx, SearchRequest{Query: "lo", Mode: "bogus"})
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || !errors.Is(err, ErrInvalidParameter) {
+			t.Fatalf("expected ErrInvalidParameter, got %v", err)
+		}
+		if len(apiErr.Problem.InvalidParams) != 1 || apiErr.Problem.InvalidParams[0].Name != "mode" {
+			t.Errorf("expected mode to be the invalid parameter, got %+v", apiErr.Problem.InvalidParams)
+		}
+
+		_, err = c.Distance(ctx, "Newport", "London")
+		if !errors.As(err, &apiErr) || !errors.Is(err, ErrAmbiguousPlace) {
+			t.Fatalf("expected ErrAmbiguousPlace, got %v", err)
+		}
+		if len(apiErr.Problem.Candidates["from"]) != 2 {
+			t.Errorf("expected two candidates, got %+v", apiErr.Problem.Candidates)
+		}
+
+		_, err = c.Distance(ctx, "Luton", "London")
+		if !errors.Is(err, ErrNoLocation) {
+			t.Errorf("expected ErrNoLocation, got %v", err)
+		}
+	})
+
+	t.Run("retries transient failures", func(t *testing.T) {
+		var failures atomic.Int32
+		server, requests := newTestServer(t, func(w http.ResponseWriter, r *http.Request) bool {
+			if failures.Add(1) <= 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return true
+			}
+			return false
+		})
+		c := newTestClient(t, server)
+
+		if _, err := c.Search(ctx, SearchRequest{Query: "ox"}); err != nil {
+			t.Fatalf("expected success after retries, got %v", err)
+		}
+		if requests.Load() != 3 {
+			t.Errorf("expected 3 requests, got %d", requests.Load())
+		}
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		server, requests := newTestServer(t, func(w http.ResponseWriter, r *http.Request) bool {
+			w.WriteHeader(http.StatusBadGateway)
+			return true
+		})
+		c := newTestClient(t, server)
+
+		_, err := c.Search(ctx, SearchRequest{Query: "ox"})
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+			t.Errorf("expected a 502 error, got %v", err)
+		}
+		if requests.Load() != 3 {
+			t.Errorf("expected 3 requests, got %d", requests.Load())
+		}
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		server, requests := newTestServer(t, nil)
+		c := newTestClient(t, server)
+
+		_, _ = c.Place(ctx, "nope")
+		if requests.Load() != 1 {
+			t.Errorf("expected 1 request, got %d", requests.Load())
+		}
+	})
+
+	t.Run("context cancellation", func(t *testing.T) {
+		server, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) bool {
+			<-r.Context().Done()
+			return true
+		})
+		c := newTestClient(t, server)
+
+		ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		if _, err := c.Search(ctx, SearchRequest{Query: "ox"}); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected context.DeadlineExceeded, got %v", err)
+		}
+	})
+
+	t.Run("caches search responses", func(t *testing.T) {
+		server, requests := newTestServer(t, nil)
+		c := newTestClient(t, server, WithCache(10, time.Minute))
+
+		for range 3 {
+			if _, err := c.Search(ctx, SearchRequest{Query: "ox"}); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+		if _, err := c.Search(ctx, SearchRequest{Query: "cr"}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if requests.Load() != 2 {
+			t.Errorf("expected 2 requests, got %d", requests.Load())
+		}
+	})
+
+	t.Run("cached responses are copies", func(t *testing.T) {
+		server, _ := newTestServer(t, nil)
+		c := newTestClient(t, server, WithCache(10, time.Minute))
+
+		first, err := c.Search(ctx, SearchRequest{Query: "ox", Envelope: true})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		first.Results[0].Name = "Changed"
+		first.Results = first.Results[:0]
+		first.Meta.Query = "changed"
+
+		second, err := c.Search(ctx, SearchRequest{Query: "ox", Envelope: true})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(second.Results) != 1 || second.Results[0].Name == "Changed" || second.Meta.Query != "ox" {
+			t.Errorf("expected the cached response to be unchanged, got %+v", second)
+		}
+	})
+
+	t.Run("invalid base URL", func(t *testing.T) {
+		if _, err := New("localhost:8080"); err == nil {
+			t.Error("expected an error for a base URL without a scheme")
+		}
+	})
+}
+
+func TestResponseCache(t *testing.T) {
+	now := time.Now()
+	cache := newResponseCache(2, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	cache.put("a", &PlaceResponse{MatchType: "a"})
+	cache.put("b", &PlaceResponse{MatchType: "b"})
+	cache.get("a") // a is now the most recently used
+	cache.put("c", &PlaceResponse{MatchType: "c"})
+
+	if _, ok := cache.get("b"); ok {
+		t.Error("expected b to be evicted")
+	}
+	if resp, ok := cache.get("a"); !ok || resp.MatchType != "a" {
+		t.Errorf("expected a to be cached, got %v", resp)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := cache.get("c"); ok {
+		t.Error("expected c to have expired")
+	}
+}
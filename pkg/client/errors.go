@@ -0,0 +1,62 @@
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/map-services/placenames-api/pkg/api"
+)
+
+// Problem codes returned by the API
+const (
+	CodeInvalidParameter     = api.CodeInvalidParameter
+	CodeInvalidBody          = api.CodeInvalidBody
+	CodeBodyTooLarge         = api.CodeBodyTooLarge
+	CodeUnsupportedQuery     = api.CodeUnsupportedQuery
+	CodeNotFound             = api.CodeNotFound
+	CodeAmbiguousPlace       = api.CodeAmbiguousPlace
+	CodeNoLocation           = api.CodeNoLocation
+	CodeRouteNotFound        = api.CodeRouteNotFound
+	CodeMethodNotAllowed     = api.CodeMethodNotAllowed
+	CodeUpgradeRequired      = api.CodeUpgradeRequired
+	CodeUnsupportedMediaType = api.CodeUnsupportedMediaType
+	CodeInternalError        = api.CodeInternalError
+)
+
+// Error is returned for any non-2xx response. Use errors.Is with the
+// sentinel errors below to branch on the problem code, or errors.As to get
+// at the full problem (e.g. the invalid parameters or distance candidates).
+type Error struct {
+	StatusCode int
+	Problem    Problem
+
+	retryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	if e.Problem.Detail != "" {
+		return fmt.Sprintf("placenames: %d %s: %s", e.StatusCode, e.Problem.Code, e.Problem.Detail)
+	}
+	return fmt.Sprintf("placenames: %d %s", e.StatusCode, e.Problem.Code)
+}
+
+// Is matches errors by problem code
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Problem.Code != "" && t.Problem.Code == e.Problem.Code
+}
+
+func codeError(code string) *Error {
+	return &Error{Problem: Problem{Code: code}}
+}
+
+var (
+	ErrInvalidParameter = codeError(CodeInvalidParameter)
+	ErrInvalidBody      = codeError(CodeInvalidBody)
+	ErrBodyTooLarge     = codeError(CodeBodyTooLarge)
+	ErrUnsupportedQuery = codeError(CodeUnsupportedQuery)
+	ErrNotFound         = codeError(CodeNotFound)
+	ErrAmbiguousPlace   = codeError(CodeAmbiguousPlace)
+	ErrNoLocation       = codeError(CodeNoLocation)
+	ErrInternal         = codeError(CodeInternalError)
+)
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/map-services/placenames-api/pkg/api"
+)
+
+// Response types are shared with the server, through the api package.
+type (
+	Result           = api.Result
+	ResponseMeta     = api.ResponseMeta
+	PlaceResponse    = api.PlaceResponse
+	PlaceDetail      = api.PlaceDetail
+	LookupResponse   = api.LookupResponse
+	DistanceResponse = api.DistanceResponse
+	Problem          = api.Problem
+	InvalidParam     = api.InvalidParam
+	Geometry         = api.Geometry
+	ShapeRequest     = api.SearchRequest
+)
+
+// Search modes
+const (
+	ModeAuto       = api.ModeAuto
+	ModePrefix     = api.ModePrefix
+	ModeExact      = api.ModeExact
+	ModeCoordinate = api.ModeCoordinate
+	ModeGridRef    = api.ModeGridRef
+)
+
+// Ranking options
+const (
+	RankRelevancy = api.RankRelevancy
+	RankBlended   = api.RankBlended
+)
+
+// Optional fields
+const (
+	FieldGridRef = api.FieldGridRef
+	FieldGeohash = api.FieldGeohash
+)
+
+// Filter restricts results to an area or place types. Empty fields don't
+// filter.
+type Filter struct {
+	Country        string
+	Region         string
+	County         string
+	LocalAuthority string
+	NationalPark   string
+	Types          []string
+	Geohash        string
+}
+
+func (f Filter) encode(values url.Values) {
+	setIf(values, "country", f.Country)
+	setIf(values, "region", f.Region)
+	setIf(values, "county", f.County)
+	setIf(values, "lad", f.LocalAuthority)
+	setIf(values, "npark", f.NationalPark)
+	setIf(values, "types", strings.Join(f.Types, ","))
+	setIf(values, "geohash", f.Geohash)
+}
+
+// SearchRequest holds the options for GET /v1/place-names. Zero values use
+// the server defaults.
+type SearchRequest struct {
+	Query      string
+	MaxResults int
+	Offset     int
+	Cursor     string // the NextCursor of a previous page, for the same search
+	Mode       string
+	Rank       string
+	Fields     []string
+	Envelope   bool
+	Filter     Filter
+}
+
+func (req SearchRequest) encode() url.Values {
+	values := url.Values{"q": {req.Query}}
+	if req.MaxResults > 0 {
+		values.Set("max_results", strconv.Itoa(req.MaxResults))
+	}
+	if req.Offset > 0 {
+		values.Set("offset", strconv.Itoa(req.Offset))
+	}
+	setIf(values, "cursor", req.Cursor)
+	setIf(values, "mode", req.Mode)
+	setIf(values, "rank", req.Rank)
+	setIf(values, "fields", strings.Join(req.Fields, ","))
+	if req.Envelope {
+		values.Set("envelope", "true")
+	}
+	req.Filter.encode(values)
+	return values
+}
+
+// NearbyRequest holds the options for GET /v1/place-names/:id/nearby
+type NearbyRequest struct {
+	RadiusKm   float64
+	MaxResults int
+	Order      string // distance (default) or relevancy
+	Fields     []string
+	Filter     Filter
+}
+
+func (req NearbyRequest) encode() url.Values {
+	values := url.Values{}
+	if req.RadiusKm > 0 {
+		values.Set("radius_km", strconv.FormatFloat(req.RadiusKm, 'f', -1, 64))
+	}
+	if req.MaxResults > 0 {
+		values.Set("max_results", strconv.Itoa(req.MaxResults))
+	}
+	setIf(values, "order", req.Order)
+	setIf(values, "fields", strings.Join(req.Fields, ","))
+	req.Filter.encode(values)
+	return values
+}
+
+func setIf(values url.Values, key, value string) {
+	if value != "" {
+		values.Set(key, value)
+	}
+}
@@ -0,0 +1,844 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: placenames/v1/placenames.proto
+
+package placenamesv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Filter restricts results to an area or place types. Empty fields don't
+// filter, and matching is case-insensitive.
+type Filter struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Country        string                 `protobuf:"bytes,1,opt,name=country,proto3" json:"country,omitempty"`
+	Region         string                 `protobuf:"bytes,2,opt,name=region,proto3" json:"region,omitempty"`
+	County         string                 `protobuf:"bytes,3,opt,name=county,proto3" json:"county,omitempty"`
+	LocalAuthority string                 `protobuf:"bytes,4,opt,name=local_authority,json=localAuthority,proto3" json:"local_authority,omitempty"`
+	NationalPark   string                 `protobuf:"bytes,5,opt,name=national_park,json=nationalPark,proto3" json:"national_park,omitempty"`
+	Types          []string               `protobuf:"bytes,6,rep,name=types,proto3" json:"types,omitempty"`
+	Geohash        string                 `protobuf:"bytes,7,opt,name=geohash,proto3" json:"geohash,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Filter) Reset() {
+	*x = Filter{}
+	mi := &file_placenames_v1_placenames_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Filter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Filter) ProtoMessage() {}
+
+func (x *Filter) ProtoReflect() protoreflect.Message {
+	mi := &file_placenames_v1_placenames_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Filter.ProtoReflect.Descriptor instead.
+func (*Filter) Descriptor() ([]byte, []int) {
+	return file_placenames_v1_placenames_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Filter) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Filter) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *Filter) GetCounty() string {
+	if x != nil {
+		return x.County
+	}
+	return ""
+}
+
+func (x *Filter) GetLocalAuthority() string {
+	if x != nil {
+		return x.LocalAuthority
+	}
+	return ""
+}
+
+func (x *Filter) GetNationalPark() string {
+	if x != nil {
+		return x.NationalPark
+	}
+	return ""
+}
+
+func (x *Filter) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *Filter) GetGeohash() string {
+	if x != nil {
+		return x.Geohash
+	}
+	return ""
+}
+
+type Place struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Relevancy      float64                `protobuf:"fixed64,3,opt,name=relevancy,proto3" json:"relevancy,omitempty"`
+	Type           string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	Lat            *float64               `protobuf:"fixed64,5,opt,name=lat,proto3,oneof" json:"lat,omitempty"`
+	Long           *float64               `protobuf:"fixed64,6,opt,name=long,proto3,oneof" json:"long,omitempty"`
+	Country        string                 `protobuf:"bytes,7,opt,name=country,proto3" json:"country,omitempty"`
+	Region         string                 `protobuf:"bytes,8,opt,name=region,proto3" json:"region,omitempty"`
+	County         string                 `protobuf:"bytes,9,opt,name=county,proto3" json:"county,omitempty"`
+	LocalAuthority string                 `protobuf:"bytes,10,opt,name=local_authority,json=localAuthority,proto3" json:"local_authority,omitempty"`
+	NationalPark   string                 `protobuf:"bytes,11,opt,name=national_park,json=nationalPark,proto3" json:"national_park,omitempty"`
+	Easting        *float64               `protobuf:"fixed64,12,opt,name=easting,proto3,oneof" json:"easting,omitempty"`
+	Northing       *float64               `protobuf:"fixed64,13,opt,name=northing,proto3,oneof" json:"northing,omitempty"`
+	// Only set by the HTTP API, when requested with ?fields= or for a single
+	// place.
+	GridRef string `protobuf:"bytes,14,opt,name=grid_ref,json=gridRef,proto3" json:"grid_ref,omitempty"`
+	Geohash string `protobuf:"bytes,15,opt,name=geohash,proto3" json:"geohash,omitempty"`
+	// Only set by the HTTP API, for coordinate, grid reference and nearby
+	// searches.
+	DistanceKm    *float64 `protobuf:"fixed64,16,opt,name=distance_km,json=distanceKm,proto3,oneof" json:"distance_km,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Place) Reset() {
+	*x = Place{}
+	mi := &file_placenames_v1_placenames_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Place) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Place) ProtoMessage() {}
+
+func (x *Place) ProtoReflect() protoreflect.Message {
+	mi := &file_placenames_v1_placenames_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Place.ProtoReflect.Descriptor instead.
+func (*Place) Descriptor() ([]byte, []int) {
+	return file_placenames_v1_placenames_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Place) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Place) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Place) GetRelevancy() float64 {
+	if x != nil {
+		return x.Relevancy
+	}
+	return 0
+}
+
+func (x *Place) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Place) GetLat() float64 {
+	if x != nil && x.Lat != nil {
+		return *x.Lat
+	}
+	return 0
+}
+
+func (x *Place) GetLong() float64 {
+	if x != nil && x.Long != nil {
+		return *x.Long
+	}
+	return 0
+}
+
+func (x *Place) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Place) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *Place) GetCounty() string {
+	if x != nil {
+		return x.County
+	}
+	return ""
+}
+
+func (x *Place) GetLocalAuthority() string {
+	if x != nil {
+		return x.LocalAuthority
+	}
+	return ""
+}
+
+func (x *Place) GetNationalPark() string {
+	if x != nil {
+		return x.NationalPark
+	}
+	return ""
+}
+
+func (x *Place) GetEasting() float64 {
+	if x != nil && x.Easting != nil {
+		return *x.Easting
+	}
+	return 0
+}
+
+func (x *Place) GetNorthing() float64 {
+	if x != nil && x.Northing != nil {
+		return *x.Northing
+	}
+	return 0
+}
+
+func (x *Place) GetGridRef() string {
+	if x != nil {
+		return x.GridRef
+	}
+	return ""
+}
+
+func (x *Place) GetGeohash() string {
+	if x != nil {
+		return x.Geohash
+	}
+	return ""
+}
+
+func (x *Place) GetDistanceKm() float64 {
+	if x != nil && x.DistanceKm != nil {
+		return *x.DistanceKm
+	}
+	return 0
+}
+
+// PlaceList is the application/x-protobuf form of the HTTP API's responses
+// containing places. Responses for a single place are a Place.
+type PlaceList struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	MatchType  string                 `protobuf:"bytes,1,opt,name=match_type,json=matchType,proto3" json:"match_type,omitempty"`
+	Results    []*Place               `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	NextCursor string                 `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	Meta       *ResponseMeta          `protobuf:"bytes,4,opt,name=meta,proto3" json:"meta,omitempty"`
+	// Only set for lookups
+	NotFound      []string `protobuf:"bytes,5,rep,name=not_found,json=notFound,proto3" json:"not_found,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlaceList) Reset() {
+	*x = PlaceList{}
+	mi := &file_placenames_v1_placenames_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlaceList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceList) ProtoMessage() {}
+
+func (x *PlaceList) ProtoReflect() protoreflect.Message {
+	mi := &file_placenames_v1_placenames_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceList.ProtoReflect.Descriptor instead.
+func (*PlaceList) Descriptor() ([]byte, []int) {
+	return file_placenames_v1_placenames_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PlaceList) GetMatchType() string {
+	if x != nil {
+		return x.MatchType
+	}
+	return ""
+}
+
+func (x *PlaceList) GetResults() []*Place {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *PlaceList) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *PlaceList) GetMeta() *ResponseMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *PlaceList) GetNotFound() []string {
+	if x != nil {
+		return x.NotFound
+	}
+	return nil
+}
+
+// ResponseMeta is the optional envelope, requested with ?envelope=true
+type ResponseMeta struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Query          string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Mode           string                 `protobuf:"bytes,2,opt,name=mode,proto3" json:"mode,omitempty"`
+	MaxResults     int32                  `protobuf:"varint,3,opt,name=max_results,json=maxResults,proto3" json:"max_results,omitempty"`
+	Offset         int32                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	Total          *int32                 `protobuf:"varint,5,opt,name=total,proto3,oneof" json:"total,omitempty"`
+	Truncated      bool                   `protobuf:"varint,6,opt,name=truncated,proto3" json:"truncated,omitempty"`
+	DatasetVersion string                 `protobuf:"bytes,7,opt,name=dataset_version,json=datasetVersion,proto3" json:"dataset_version,omitempty"`
+	TookMs         float64                `protobuf:"fixed64,8,opt,name=took_ms,json=tookMs,proto3" json:"took_ms,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ResponseMeta) Reset() {
+	*x = ResponseMeta{}
+	mi := &file_placenames_v1_placenames_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponseMeta) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseMeta) ProtoMessage() {}
+
+func (x *ResponseMeta) ProtoReflect() protoreflect.Message {
+	mi := &file_placenames_v1_placenames_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseMeta.ProtoReflect.Descriptor instead.
+func (*ResponseMeta) Descriptor() ([]byte, []int) {
+	return file_placenames_v1_placenames_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ResponseMeta) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *ResponseMeta) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *ResponseMeta) GetMaxResults() int32 {
+	if x != nil {
+		return x.MaxResults
+	}
+	return 0
+}
+
+func (x *ResponseMeta) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ResponseMeta) GetTotal() int32 {
+	if x != nil && x.Total != nil {
+		return *x.Total
+	}
+	return 0
+}
+
+func (x *ResponseMeta) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *ResponseMeta) GetDatasetVersion() string {
+	if x != nil {
+		return x.DatasetVersion
+	}
+	return ""
+}
+
+func (x *ResponseMeta) GetTookMs() float64 {
+	if x != nil {
+		return x.TookMs
+	}
+	return 0
+}
+
+type SuggestRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Defaults to 10, and can be no more than the server's top-K.
+	MaxResults    int32   `protobuf:"varint,2,opt,name=max_results,json=maxResults,proto3" json:"max_results,omitempty"`
+	Filter        *Filter `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestRequest) Reset() {
+	*x = SuggestRequest{}
+	mi := &file_placenames_v1_placenames_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestRequest) ProtoMessage() {}
+
+func (x *SuggestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_placenames_v1_placenames_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestRequest.ProtoReflect.Descriptor instead.
+func (*SuggestRequest) Descriptor() ([]byte, []int) {
+	return file_placenames_v1_placenames_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SuggestRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SuggestRequest) GetMaxResults() int32 {
+	if x != nil {
+		return x.MaxResults
+	}
+	return 0
+}
+
+func (x *SuggestRequest) GetFilter() *Filter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type SuggestResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*Place               `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestResponse) Reset() {
+	*x = SuggestResponse{}
+	mi := &file_placenames_v1_placenames_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestResponse) ProtoMessage() {}
+
+func (x *SuggestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_placenames_v1_placenames_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestResponse.ProtoReflect.Descriptor instead.
+func (*SuggestResponse) Descriptor() ([]byte, []int) {
+	return file_placenames_v1_placenames_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SuggestResponse) GetResults() []*Place {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type AutocompleteRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Defaults to 10, and can be no more than the server's top-K.
+	MaxResults int32   `protobuf:"varint,2,opt,name=max_results,json=maxResults,proto3" json:"max_results,omitempty"`
+	Filter     *Filter `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Echoed back in the response, so clients can match results to keystrokes.
+	Sequence      uint64 `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AutocompleteRequest) Reset() {
+	*x = AutocompleteRequest{}
+	mi := &file_placenames_v1_placenames_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AutocompleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AutocompleteRequest) ProtoMessage() {}
+
+func (x *AutocompleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_placenames_v1_placenames_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AutocompleteRequest.ProtoReflect.Descriptor instead.
+func (*AutocompleteRequest) Descriptor() ([]byte, []int) {
+	return file_placenames_v1_placenames_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AutocompleteRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *AutocompleteRequest) GetMaxResults() int32 {
+	if x != nil {
+		return x.MaxResults
+	}
+	return 0
+}
+
+func (x *AutocompleteRequest) GetFilter() *Filter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *AutocompleteRequest) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+type AutocompleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      uint64                 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Query         string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	Results       []*Place               `protobuf:"bytes,3,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AutocompleteResponse) Reset() {
+	*x = AutocompleteResponse{}
+	mi := &file_placenames_v1_placenames_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AutocompleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AutocompleteResponse) ProtoMessage() {}
+
+func (x *AutocompleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_placenames_v1_placenames_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AutocompleteResponse.ProtoReflect.Descriptor instead.
+func (*AutocompleteResponse) Descriptor() ([]byte, []int) {
+	return file_placenames_v1_placenames_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AutocompleteResponse) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *AutocompleteResponse) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *AutocompleteResponse) GetResults() []*Place {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+var File_placenames_v1_placenames_proto protoreflect.FileDescriptor
+
+const file_placenames_v1_placenames_proto_rawDesc = "" +
+	"\n" +
+	"\x1eplacenames/v1/placenames.proto\x12\rplacenames.v1\"\xd0\x01\n" +
+	"\x06Filter\x12\x18\n" +
+	"\acountry\x18\x01 \x01(\tR\acountry\x12\x16\n" +
+	"\x06region\x18\x02 \x01(\tR\x06region\x12\x16\n" +
+	"\x06county\x18\x03 \x01(\tR\x06county\x12'\n" +
+	"\x0flocal_authority\x18\x04 \x01(\tR\x0elocalAuthority\x12#\n" +
+	"\rnational_park\x18\x05 \x01(\tR\fnationalPark\x12\x14\n" +
+	"\x05types\x18\x06 \x03(\tR\x05types\x12\x18\n" +
+	"\ageohash\x18\a \x01(\tR\ageohash\"\xfa\x03\n" +
+	"\x05Place\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1c\n" +
+	"\trelevancy\x18\x03 \x01(\x01R\trelevancy\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\tR\x04type\x12\x15\n" +
+	"\x03lat\x18\x05 \x01(\x01H\x00R\x03lat\x88\x01\x01\x12\x17\n" +
+	"\x04long\x18\x06 \x01(\x01H\x01R\x04long\x88\x01\x01\x12\x18\n" +
+	"\acountry\x18\a \x01(\tR\acountry\x12\x16\n" +
+	"\x06region\x18\b \x01(\tR\x06region\x12\x16\n" +
+	"\x06county\x18\t \x01(\tR\x06county\x12'\n" +
+	"\x0flocal_authority\x18\n" +
+	" \x01(\tR\x0elocalAuthority\x12#\n" +
+	"\rnational_park\x18\v \x01(\tR\fnationalPark\x12\x1d\n" +
+	"\aeasting\x18\f \x01(\x01H\x02R\aeasting\x88\x01\x01\x12\x1f\n" +
+	"\bnorthing\x18\r \x01(\x01H\x03R\bnorthing\x88\x01\x01\x12\x19\n" +
+	"\bgrid_ref\x18\x0e \x01(\tR\agridRef\x12\x18\n" +
+	"\ageohash\x18\x0f \x01(\tR\ageohash\x12$\n" +
+	"\vdistance_km\x18\x10 \x01(\x01H\x04R\n" +
+	"distanceKm\x88\x01\x01B\x06\n" +
+	"\x04_latB\a\n" +
+	"\x05_longB\n" +
+	"\n" +
+	"\b_eastingB\v\n" +
+	"\t_northingB\x0e\n" +
+	"\f_distance_km\"\xc9\x01\n" +
+	"\tPlaceList\x12\x1d\n" +
+	"\n" +
+	"match_type\x18\x01 \x01(\tR\tmatchType\x12.\n" +
+	"\aresults\x18\x02 \x03(\v2\x14.placenames.v1.PlaceR\aresults\x12\x1f\n" +
+	"\vnext_cursor\x18\x03 \x01(\tR\n" +
+	"nextCursor\x12/\n" +
+	"\x04meta\x18\x04 \x01(\v2\x1b.placenames.v1.ResponseMetaR\x04meta\x12\x1b\n" +
+	"\tnot_found\x18\x05 \x03(\tR\bnotFound\"\xf6\x01\n" +
+	"\fResponseMeta\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x12\n" +
+	"\x04mode\x18\x02 \x01(\tR\x04mode\x12\x1f\n" +
+	"\vmax_results\x18\x03 \x01(\x05R\n" +
+	"maxResults\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\x12\x19\n" +
+	"\x05total\x18\x05 \x01(\x05H\x00R\x05total\x88\x01\x01\x12\x1c\n" +
+	"\ttruncated\x18\x06 \x01(\bR\ttruncated\x12'\n" +
+	"\x0fdataset_version\x18\a \x01(\tR\x0edatasetVersion\x12\x17\n" +
+	"\atook_ms\x18\b \x01(\x01R\x06tookMsB\b\n" +
+	"\x06_total\"v\n" +
+	"\x0eSuggestRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1f\n" +
+	"\vmax_results\x18\x02 \x01(\x05R\n" +
+	"maxResults\x12-\n" +
+	"\x06filter\x18\x03 \x01(\v2\x15.placenames.v1.FilterR\x06filter\"A\n" +
+	"\x0fSuggestResponse\x12.\n" +
+	"\aresults\x18\x01 \x03(\v2\x14.placenames.v1.PlaceR\aresults\"\x97\x01\n" +
+	"\x13AutocompleteRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1f\n" +
+	"\vmax_results\x18\x02 \x01(\x05R\n" +
+	"maxResults\x12-\n" +
+	"\x06filter\x18\x03 \x01(\v2\x15.placenames.v1.FilterR\x06filter\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x04R\bsequence\"x\n" +
+	"\x14AutocompleteResponse\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x04R\bsequence\x12\x14\n" +
+	"\x05query\x18\x02 \x01(\tR\x05query\x12.\n" +
+	"\aresults\x18\x03 \x03(\v2\x14.placenames.v1.PlaceR\aresults2\xba\x01\n" +
+	"\x11PlaceNamesService\x12H\n" +
+	"\aSuggest\x12\x1d.placenames.v1.SuggestRequest\x1a\x1e.placenames.v1.SuggestResponse\x12[\n" +
+	"\fAutocomplete\x12\".placenames.v1.AutocompleteRequest\x1a#.placenames.v1.AutocompleteResponse(\x010\x01BIZGgithub.com/map-services/placenames-api/pkg/pb/placenamesv1;placenamesv1b\x06proto3"
+
+var (
+	file_placenames_v1_placenames_proto_rawDescOnce sync.Once
+	file_placenames_v1_placenames_proto_rawDescData []byte
+)
+
+func file_placenames_v1_placenames_proto_rawDescGZIP() []byte {
+	file_placenames_v1_placenames_proto_rawDescOnce.Do(func() {
+		file_placenames_v1_placenames_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_placenames_v1_placenames_proto_rawDesc), len(file_placenames_v1_placenames_proto_rawDesc)))
+	})
+	return file_placenames_v1_placenames_proto_rawDescData
+}
+
+var file_placenames_v1_placenames_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_placenames_v1_placenames_proto_goTypes = []any{
+	(*Filter)(nil),               // 0: placenames.v1.Filter
+	(*Place)(nil),                // 1: placenames.v1.Place
+	(*PlaceList)(nil),            // 2: placenames.v1.PlaceList
+	(*ResponseMeta)(nil),         // 3: placenames.v1.ResponseMeta
+	(*SuggestRequest)(nil),       // 4: placenames.v1.SuggestRequest
+	(*SuggestResponse)(nil),      // 5: placenames.v1.SuggestResponse
+	(*AutocompleteRequest)(nil),  // 6: placenames.v1.AutocompleteRequest
+	(*AutocompleteResponse)(nil), // 7: placenames.v1.AutocompleteResponse
+}
+var file_placenames_v1_placenames_proto_depIdxs = []int32{
+	1, // 0: placenames.v1.PlaceList.results:type_name -> placenames.v1.Place
+	3, // 1: placenames.v1.PlaceList.meta:type_name -> placenames.v1.ResponseMeta
+	0, // 2: placenames.v1.SuggestRequest.filter:type_name -> placenames.v1.Filter
+	1, // 3: placenames.v1.SuggestResponse.results:type_name -> placenames.v1.Place
+	0, // 4: placenames.v1.AutocompleteRequest.filter:type_name -> placenames.v1.Filter
+	1, // 5: placenames.v1.AutocompleteResponse.results:type_name -> placenames.v1.Place
+	4, // 6: placenames.v1.PlaceNamesService.Suggest:input_type -> placenames.v1.SuggestRequest
+	6, // 7: placenames.v1.PlaceNamesService.Autocomplete:input_type -> placenames.v1.AutocompleteRequest
+	5, // 8: placenames.v1.PlaceNamesService.Suggest:output_type -> placenames.v1.SuggestResponse
+	7, // 9: placenames.v1.PlaceNamesService.Autocomplete:output_type -> placenames.v1.AutocompleteResponse
+	8, // [8:10] is the sub-list for method output_type
+	6, // [6:8] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_placenames_v1_placenames_proto_init() }
+func file_placenames_v1_placenames_proto_init() {
+	if File_placenames_v1_placenames_proto != nil {
+		return
+	}
+	file_placenames_v1_placenames_proto_msgTypes[1].OneofWrappers = []any{}
+	file_placenames_v1_placenames_proto_msgTypes[3].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_placenames_v1_placenames_proto_rawDesc), len(file_placenames_v1_placenames_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_placenames_v1_placenames_proto_goTypes,
+		DependencyIndexes: file_placenames_v1_placenames_proto_depIdxs,
+		MessageInfos:      file_placenames_v1_placenames_proto_msgTypes,
+	}.Build()
+	File_placenames_v1_placenames_proto = out.File
+	file_placenames_v1_placenames_proto_goTypes = nil
+	file_placenames_v1_placenames_proto_depIdxs = nil
+}
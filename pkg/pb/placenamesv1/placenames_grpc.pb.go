@@ -0,0 +1,168 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: placenames/v1/placenames.proto
+
+package placenamesv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PlaceNamesService_Suggest_FullMethodName      = "/placenames.v1.PlaceNamesService/Suggest"
+	PlaceNamesService_Autocomplete_FullMethodName = "/placenames.v1.PlaceNamesService/Autocomplete"
+)
+
+// PlaceNamesServiceClient is the client API for PlaceNamesService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PlaceNamesService offers the place name autosuggest over gRPC, backed by
+// the same trie as the HTTP API.
+type PlaceNamesServiceClient interface {
+	// Suggest returns the most relevant places starting with the query.
+	Suggest(ctx context.Context, in *SuggestRequest, opts ...grpc.CallOption) (*SuggestResponse, error)
+	// Autocomplete takes the query as it is typed, and pushes the top results
+	// for each keystroke. Keystrokes superseded before their search finishes
+	// are dropped, so every response is for the latest query seen so far.
+	Autocomplete(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[AutocompleteRequest, AutocompleteResponse], error)
+}
+
+type placeNamesServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPlaceNamesServiceClient(cc grpc.ClientConnInterface) PlaceNamesServiceClient {
+	return &placeNamesServiceClient{cc}
+}
+
+func (c *placeNamesServiceClient) Suggest(ctx context.Context, in *SuggestRequest, opts ...grpc.CallOption) (*SuggestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SuggestResponse)
+	err := c.cc.Invoke(ctx, PlaceNamesService_Suggest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *placeNamesServiceClient) Autocomplete(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[AutocompleteRequest, AutocompleteResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PlaceNamesService_ServiceDesc.Streams[0], PlaceNamesService_Autocomplete_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[AutocompleteRequest, AutocompleteResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PlaceNamesService_AutocompleteClient = grpc.BidiStreamingClient[AutocompleteRequest, AutocompleteResponse]
+
+// PlaceNamesServiceServer is the server API for PlaceNamesService service.
+// All implementations must embed UnimplementedPlaceNamesServiceServer
+// for forward compatibility.
+//
+// PlaceNamesService offers the place name autosuggest over gRPC, backed by
+// the same trie as the HTTP API.
+type PlaceNamesServiceServer interface {
+	// Suggest returns the most relevant places starting with the query.
+	Suggest(context.Context, *SuggestRequest) (*SuggestResponse, error)
+	// Autocomplete takes the query as it is typed, and pushes the top results
+	// for each keystroke. Keystrokes superseded before their search finishes
+	// are dropped, so every response is for the latest query seen so far.
+	Autocomplete(grpc.BidiStreamingServer[AutocompleteRequest, AutocompleteResponse]) error
+	mustEmbedUnimplementedPlaceNamesServiceServer()
+}
+
+// UnimplementedPlaceNamesServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPlaceNamesServiceServer struct{}
+
+func (UnimplementedPlaceNamesServiceServer) Suggest(context.Context, *SuggestRequest) (*SuggestResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Suggest not implemented")
+}
+func (UnimplementedPlaceNamesServiceServer) Autocomplete(grpc.BidiStreamingServer[AutocompleteRequest, AutocompleteResponse]) error {
+	return status.Error(codes.Unimplemented, "method Autocomplete not implemented")
+}
+func (UnimplementedPlaceNamesServiceServer) mustEmbedUnimplementedPlaceNamesServiceServer() {}
+func (UnimplementedPlaceNamesServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafePlaceNamesServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PlaceNamesServiceServer will
+// result in compilation errors.
+type UnsafePlaceNamesServiceServer interface {
+	mustEmbedUnimplementedPlaceNamesServiceServer()
+}
+
+func RegisterPlaceNamesServiceServer(s grpc.ServiceRegistrar, srv PlaceNamesServiceServer) {
+	// If the following call panics, it indicates UnimplementedPlaceNamesServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PlaceNamesService_ServiceDesc, srv)
+}
+
+func _PlaceNamesService_Suggest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlaceNamesServiceServer).Suggest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlaceNamesService_Suggest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlaceNamesServiceServer).Suggest(ctx, req.(*SuggestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlaceNamesService_Autocomplete_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PlaceNamesServiceServer).Autocomplete(&grpc.GenericServerStream[AutocompleteRequest, AutocompleteResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PlaceNamesService_AutocompleteServer = grpc.BidiStreamingServer[AutocompleteRequest, AutocompleteResponse]
+
+// PlaceNamesService_ServiceDesc is the grpc.ServiceDesc for PlaceNamesService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PlaceNamesService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "placenames.v1.PlaceNamesService",
+	HandlerType: (*PlaceNamesServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Suggest",
+			Handler:    _PlaceNamesService_Suggest_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Autocomplete",
+			Handler:       _PlaceNamesService_Autocomplete_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "placenames/v1/placenames.proto",
+}